@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	rightRead   = "read"
+	rightEdit   = "edit"
+	rightDelete = "delete"
+	rightAdmin  = "admin"
+
+	// principalEveryone matches anyone, including anonymous users, and
+	// principalUsers any authenticated user.
+	principalEveryone = "*"
+	principalUsers    = "@users"
+)
+
+var rights = []string{rightRead, rightEdit, rightDelete, rightAdmin}
+
+// ACLRule grants rights on the pages whose title starts with Prefix. Allow
+// maps each right to the users and groups (prefixed with @) holding it.
+type ACLRule struct {
+	Prefix string              `json:"prefix"`
+	Allow  map[string][]string `json:"allow"`
+}
+
+// ACL controls access to pages with rules read from a JSON file.
+type ACL struct {
+	filename string
+	fallback ACLRule
+	admins   []string
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	rules   []ACLRule
+}
+
+// NewACL returns the ACL stored in filename. fallback can read, edit and delete
+// the pages no rule matches, and admins administer them all.
+func NewACL(filename string, fallback string, admins []string) *ACL {
+	allow := make(map[string][]string)
+	for _, right := range []string{rightRead, rightEdit, rightDelete} {
+		allow[right] = []string{fallback}
+	}
+
+	return &ACL{
+		filename: filename,
+		fallback: ACLRule{Prefix: "", Allow: allow},
+		admins:   admins,
+	}
+}
+
+func parseACL(content []byte) ([]ACLRule, error) {
+	var rules []ACLRule
+	if err := json.Unmarshal(content, &rules); err != nil {
+		return nil, errors.New("Invalid ACL: " + err.Error())
+	}
+
+	for _, rule := range rules {
+		for right := range rule.Allow {
+			known := false
+			for _, r := range rights {
+				known = known || r == right
+			}
+			if !known {
+				return nil, errors.New("Invalid ACL: unknown right " + right + " for prefix " + rule.Prefix)
+			}
+		}
+	}
+	return rules, nil
+}
+
+// load returns the rules of the file, read again when it changes.
+func (a *ACL) load() ([]ACLRule, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	info, err := os.Stat(a.filename)
+	if os.IsNotExist(err) {
+		a.modTime, a.size, a.rules = time.Time{}, 0, nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.ModTime().Equal(a.modTime) || info.Size() != a.size || a.rules == nil {
+		content, err := ioutil.ReadFile(a.filename)
+		if err != nil {
+			return nil, err
+		}
+		rules, err := parseACL(content)
+		if err != nil {
+			return nil, err
+		}
+		a.modTime, a.size, a.rules = info.ModTime(), info.Size(), rules
+	}
+	return a.rules, nil
+}
+
+// rule returns the rule applying to title.
+func (a *ACL) rule(title string) (ACLRule, error) {
+	rules, err := a.load()
+	if err != nil {
+		return ACLRule{}, err
+	}
+
+	match := a.fallback
+	found := false
+	for _, rule := range rules {
+		if strings.HasPrefix(title, rule.Prefix) && (!found || len(rule.Prefix) > len(match.Prefix)) {
+			match, found = rule, true
+		}
+	}
+	return match, nil
+}
+
+func (rule ACLRule) holds(user *User, right string) bool {
+	return granted(rule.Allow[right], user)
+}
+
+// granted reports whether user, nil when anonymous, is one of principals.
+func granted(principals []string, user *User) bool {
+	for _, principal := range principals {
+		switch {
+		case principal == principalEveryone:
+			return true
+		case user == nil:
+			continue
+		case principal == principalUsers || principal == user.Name:
+			return true
+		case strings.HasPrefix(principal, "@"):
+			for _, group := range user.Groups {
+				if "@"+group == principal {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Allowed reports whether user, nil when anonymous, has right on the page
+// title.
+func (a *ACL) Allowed(user *User, title string, right string) bool {
+	if granted(a.admins, user) {
+		return true
+	}
+
+	rule, err := a.rule(title)
+	if err != nil {
+		log.Println(err)
+		return false
+	}
+
+	implied := []string{right, rightAdmin}
+	if right == rightRead {
+		implied = append(implied, rightEdit, rightDelete)
+	}
+	for _, r := range implied {
+		if rule.holds(user, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// Readable returns the titles user can read.
+func (a *ACL) Readable(user *User, titles []string) []string {
+	readable := make([]string, 0, len(titles))
+	for _, title := range titles {
+		if a.Allowed(user, title, rightRead) {
+			readable = append(readable, title)
+		}
+	}
+	return readable
+}
+
+// Source returns the content of the ACL file.
+func (a *ACL) Source() (string, error) {
+	content, err := ioutil.ReadFile(a.filename)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	return string(content), err
+}
+
+// SetSource replaces the ACL file with content once validated.
+func (a *ACL) SetSource(content string) error {
+	if _, err := parseACL([]byte(content)); err != nil {
+		return err
+	}
+	return writeFileAtomic(a.filename, []byte(content), 0600)
+}
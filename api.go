@@ -0,0 +1,277 @@
+package main
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// apiPrefix is the root of the JSON API.
+const apiPrefix = "/_/api/v1"
+
+type apiPage struct {
+	Title    string `json:"title"`
+	Revision string `json:"revision"`
+	Body     string `json:"body"`
+}
+
+type apiPageUpdate struct {
+	Body         string `json:"body"`
+	Message      string `json:"message"`
+	BaseRevision string `json:"base_revision"`
+}
+
+type apiCommit struct {
+	ID          string `json:"id"`
+	Date        string `json:"date"`
+	Message     string `json:"message"`
+	AuthorName  string `json:"author_name"`
+	AuthorEmail string `json:"author_email"`
+	Delete      bool   `json:"delete"`
+	Title       string `json:"title"`
+	RenamedFrom string `json:"renamed_from,omitempty"`
+}
+
+type apiDiff struct {
+	Title   string `json:"title"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Unified string `json:"unified"`
+}
+
+type apiSearchResult struct {
+	Title      string        `json:"title"`
+	Lines      []string      `json:"lines"`
+	Score      float64       `json:"score"`
+	Snippet    template.HTML `json:"snippet"`
+	TitleMatch bool          `json:"title_match"`
+}
+
+type apiHistorySearchResult struct {
+	Title    string        `json:"title"`
+	Revision string        `json:"revision"`
+	Date     string        `json:"date"`
+	Deleted  bool          `json:"deleted"`
+	Snippet  template.HTML `json:"snippet"`
+}
+
+// apiError is the body of the responses reporting an error.
+type apiError struct {
+	Error    string `json:"error"`
+	Revision string `json:"revision,omitempty"`
+	Merged   string `json:"merged,omitempty"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeAPIError(w http.ResponseWriter, err error) {
+	body := apiError{Error: err.Error()}
+	if conflict, ok := err.(*EditConflict); ok {
+		body.Revision = conflict.Revision
+		body.Merged = conflict.Merged
+	}
+	writeJSON(w, statusOf(err), body)
+}
+
+// apiAuthorize is like authorize, without sending anyone to the login page.
+func (app AppContext) apiAuthorize(w http.ResponseWriter, r *http.Request, title string, right string) bool {
+	user := currentUser(r)
+	if app.ACL.Allowed(user, title, right) {
+		return true
+	}
+
+	if user == nil && app.Auth.Mode != authNone {
+		if app.Auth.Mode == authLocal {
+			w.Header().Set("WWW-Authenticate", `Basic realm="wiki"`)
+		}
+		writeJSON(w, http.StatusUnauthorized, apiError{Error: "Authentication required"})
+		return false
+	}
+	writeJSON(w, http.StatusForbidden, apiError{Error: "Not allowed to " + right + " " + title})
+	return false
+}
+
+func (app AppContext) apiDeletePageHandler(w http.ResponseWriter, r *http.Request) {
+	title := mux.Vars(r)["title"]
+	if !app.apiAuthorize(w, r, title, rightDelete) {
+		return
+	}
+
+	if _, err := app.Storage.DeletePage(title, authorOf(r)); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (app AppContext) apiDeletedHandler(w http.ResponseWriter, r *http.Request) {
+	titles, err := app.Storage.ListDeletedPages()
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, app.ACL.Readable(currentUser(r), titles))
+}
+
+func (app AppContext) apiDiffHandler(w http.ResponseWriter, r *http.Request) {
+	title := mux.Vars(r)["title"]
+	if !app.apiAuthorize(w, r, title, rightRead) {
+		return
+	}
+
+	query := r.URL.Query()
+	d := apiDiff{Title: title, From: query.Get("from"), To: query.Get("to")}
+	if d.To == "" {
+		d.To = "HEAD"
+	}
+	var from *string
+	if _, ok := query["from"]; ok {
+		from = &d.From
+	}
+
+	var diff *PageDiff
+	var err error
+	d.From, diff, err = app.diffRevisions(title, from, d.To)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	d.Unified = string(diff.Unified())
+	writeJSON(w, http.StatusOK, d)
+}
+
+func (app AppContext) apiHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	title := mux.Vars(r)["title"]
+	if !app.apiAuthorize(w, r, title, rightRead) {
+		return
+	}
+
+	commits, err := app.Storage.History(title)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+
+	history := make([]apiCommit, len(commits))
+	for i, commit := range commits {
+		history[i] = apiCommit(commit)
+	}
+	writeJSON(w, http.StatusOK, history)
+}
+
+func (app AppContext) apiNotFoundHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusNotFound, apiError{Error: "Not found: " + r.URL.Path})
+}
+
+func (app AppContext) apiPageHandler(w http.ResponseWriter, r *http.Request) {
+	title := mux.Vars(r)["title"]
+	if !app.apiAuthorize(w, r, title, rightRead) {
+		return
+	}
+
+	page := apiPage{Title: title, Revision: r.URL.Query().Get("revision")}
+	if page.Revision == "" {
+		head, err := app.Storage.Head()
+		if err != nil {
+			writeAPIError(w, err)
+			return
+		}
+		page.Revision = head
+	}
+
+	body, err := app.Storage.PageBody(title, page.Revision)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	page.Body = string(body)
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (app AppContext) apiPagesHandler(w http.ResponseWriter, r *http.Request) {
+	titles, err := app.Storage.ListPages()
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, app.ACL.Readable(currentUser(r), titles))
+}
+
+// apiSavePageHandler creates or updates a page. Given the revision the edit
+// started from, concurrent changes are merged as in the editor.
+func (app AppContext) apiSavePageHandler(w http.ResponseWriter, r *http.Request) {
+	title := mux.Vars(r)["title"]
+	if !app.apiAuthorize(w, r, title, rightEdit) {
+		return
+	}
+
+	var update apiPageUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{Error: "Invalid request body: " + err.Error()})
+		return
+	}
+	if update.Message == "" {
+		update.Message = "Update " + title
+	}
+
+	revision, created, err := app.Storage.SetPageBody(title, update.Body, update.Message, update.BaseRevision, authorOf(r))
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+
+	// The body saved, once concurrent changes are merged
+	body, err := app.Storage.PageBody(title, revision)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	writeJSON(w, status, apiPage{Title: title, Revision: revision, Body: string(body)})
+}
+
+func (app AppContext) apiSearchHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+
+	if r.URL.Query().Get("history") != "" {
+		allowed := func(title string) bool {
+			return app.ACL.Allowed(currentUser(r), title, rightRead)
+		}
+
+		results, err := app.Search.SearchHistory(q, searchOptions(r), allowed, historySearchSize)
+		if err != nil {
+			writeAPIError(w, err)
+			return
+		}
+
+		found := make([]apiHistorySearchResult, len(results))
+		for i, result := range results {
+			found[i] = apiHistorySearchResult(result)
+		}
+		writeJSON(w, http.StatusOK, found)
+		return
+	}
+
+	results, err := app.Storage.Search(q, searchOptions(r))
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+
+	readable := app.readableResults(r, results)
+	found := make([]apiSearchResult, len(readable))
+	for i, result := range readable {
+		found[i] = apiSearchResult(result)
+	}
+	writeJSON(w, http.StatusOK, found)
+}
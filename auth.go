@@ -0,0 +1,334 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	authNone  = "none"
+	authLocal = "local"
+	authProxy = "proxy"
+
+	sessionCookie   = "wiki_session"
+	sessionLifetime = 7 * 24 * time.Hour
+)
+
+var (
+	errInvalidCredentials = errors.New("Invalid user name or password")
+
+	// dummyHash is checked against when there is no such user, so that
+	// failed logins take the same time either way.
+	dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy"), bcrypt.DefaultCost)
+)
+
+type contextKey int
+
+const (
+	userKey contextKey = iota
+	csrfKey
+)
+
+// User is someone acting on the wiki.
+type User struct {
+	Name         string   `json:"name"`
+	Email        string   `json:"email,omitempty"`
+	Groups       []string `json:"groups,omitempty"`
+	PasswordHash string   `json:"password_hash,omitempty"`
+}
+
+// UserStore keeps the local accounts in a JSON file.
+type UserStore struct {
+	mu       sync.Mutex
+	filename string
+}
+
+func NewUserStore(filename string) *UserStore {
+	return &UserStore{filename: filename}
+}
+
+func (s *UserStore) load() ([]User, error) {
+	content, err := ioutil.ReadFile(s.filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var users []User
+	if err := json.Unmarshal(content, &users); err != nil {
+		return nil, errors.New("Invalid users file " + s.filename + ": " + err.Error())
+	}
+	return users, nil
+}
+
+// Authenticate returns the user called name if password is theirs.
+func (s *UserStore) Authenticate(name string, password string) (*User, error) {
+	user, err := s.Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil || user.PasswordHash == "" {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return nil, errInvalidCredentials
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, errInvalidCredentials
+	}
+	return user, nil
+}
+
+// Lookup returns the user called name, or nil if there is no such user.
+func (s *UserStore) Lookup(name string) (*User, error) {
+	users, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range users {
+		if user.Name == name {
+			return &user, nil
+		}
+	}
+	return nil, nil
+}
+
+// SetUser adds or replaces the account of user, with the given password.
+func (s *UserStore) SetUser(user User, password string) error {
+	if user.Name == "" || strings.ContainsAny(user.Name, "<>\"\n") {
+		return errors.New("Invalid user name: " + user.Name)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	user.PasswordHash = string(hash)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+	for i := range users {
+		if users[i].Name == user.Name {
+			users[i] = user
+			replaced = true
+		}
+	}
+	if !replaced {
+		users = append(users, user)
+	}
+
+	content, err := json.MarshalIndent(users, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.filename, append(content, '\n'), 0600)
+}
+
+// writeFileAtomic replaces filename with content, so that readers never see
+// it half written.
+func writeFileAtomic(filename string, content []byte, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0770); err != nil {
+		return err
+	}
+
+	tmp := filename + ".tmp"
+	if err := ioutil.WriteFile(tmp, content, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
+
+type session struct {
+	name    string
+	expires time.Time
+}
+
+// Authenticator finds out who is behind each request, either from a session
+// opened with a local account or from a header set by a reverse proxy.
+type Authenticator struct {
+	Mode        string
+	Users       *UserStore
+	Header      string
+	EmailHeader string
+
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+func NewAuthenticator(mode string, users *UserStore) (*Authenticator, error) {
+	switch mode {
+	case authNone, authLocal, authProxy:
+	default:
+		return nil, errors.New("Unknown authentication mode: " + mode)
+	}
+
+	return &Authenticator{
+		Mode:     mode,
+		Users:    users,
+		sessions: make(map[string]*session),
+	}, nil
+}
+
+// currentUser returns the user behind r, or nil if they are anonymous.
+func currentUser(r *http.Request) *User {
+	user, _ := r.Context().Value(userKey).(*User)
+	return user
+}
+
+func (a *Authenticator) user(r *http.Request) (*User, error) {
+	switch a.Mode {
+	case authLocal:
+		// Scripts authenticate every request instead of opening a session
+		if name, password, ok := r.BasicAuth(); ok {
+			return a.Users.Authenticate(name, password)
+		}
+
+		cookie, err := r.Cookie(sessionCookie)
+		if err != nil {
+			return nil, nil
+		}
+
+		a.mu.Lock()
+		s, ok := a.sessions[cookie.Value]
+		if ok && time.Now().After(s.expires) {
+			delete(a.sessions, cookie.Value)
+			ok = false
+		}
+		a.mu.Unlock()
+
+		if !ok {
+			return nil, nil
+		}
+		// Accounts are looked up again so that changes apply to open sessions
+		return a.Users.Lookup(s.name)
+
+	case authProxy:
+		name := strings.TrimSpace(r.Header.Get(a.Header))
+		if name == "" {
+			return nil, nil
+		}
+
+		user, err := a.Users.Lookup(name)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			user = &User{Name: name}
+		}
+		if email := strings.TrimSpace(r.Header.Get(a.EmailHeader)); email != "" {
+			user.Email = email
+		}
+		return user, nil
+	}
+
+	return nil, nil
+}
+
+// Middleware records the user behind each request in its context. What
+// anonymous users may do is up to the access control lists.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, err := a.user(r)
+		if err == errInvalidCredentials {
+			w.Header().Set("WWW-Authenticate", `Basic realm="wiki"`)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if user != nil {
+			r = r.WithContext(context.WithValue(r.Context(), userKey, user))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Login opens a session for user.
+func (a *Authenticator) Login(w http.ResponseWriter, r *http.Request, user *User) error {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return err
+	}
+	id := hex.EncodeToString(token)
+
+	a.mu.Lock()
+	now := time.Now()
+	for key, s := range a.sessions {
+		if now.After(s.expires) {
+			delete(a.sessions, key)
+		}
+	}
+	a.sessions[id] = &session{name: user.Name, expires: now.Add(sessionLifetime)}
+	a.mu.Unlock()
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    id,
+		Path:     "/",
+		Expires:  now.Add(sessionLifetime),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	// Tokens given out before logging in must not be usable afterwards
+	_, err := setCSRFCookie(w, r)
+	return err
+}
+
+// Logout closes the session of r.
+func (a *Authenticator) Logout(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(sessionCookie); err == nil {
+		a.mu.Lock()
+		delete(a.sessions, cookie.Value)
+		a.mu.Unlock()
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+	})
+	http.SetCookie(w, &http.Cookie{
+		Name:     csrfCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+	})
+}
+
+// safeRedirect returns next if it is a path on this site, or / otherwise.
+func safeRedirect(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/"
+	}
+	return next
+}
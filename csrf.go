@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+const (
+	csrfCookie = "wiki_csrf"
+	csrfField  = "csrf_token"
+	csrfHeader = "X-CSRF-Token"
+
+	// maxRequestSize bounds the body of the requests, which is read to find
+	// the token before reaching the handlers.
+	maxRequestSize = maxAttachmentSize + 1<<20
+)
+
+// csrfToken returns the token the forms of the page answering r must carry.
+func csrfToken(r *http.Request) string {
+	token, _ := r.Context().Value(csrfKey).(string)
+	return token
+}
+
+func newCSRFToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// setCSRFCookie gives the browser behind r a new token, which only lasts as
+// long as its session.
+func setCSRFCookie(w http.ResponseWriter, r *http.Request) (string, error) {
+	token, err := newCSRFToken()
+	if err != nil {
+		return "", err
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     csrfCookie,
+		Value:    token,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return token, nil
+}
+
+// CSRFMiddleware rejects the POST requests not carrying the token of the CSRF
+// cookie in a form field or a header.
+func CSRFMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var token string
+		if cookie, err := r.Cookie(csrfCookie); err == nil {
+			token = cookie.Value
+		}
+
+		if r.Body != nil {
+			r.Body = http.MaxBytesReader(w, r.Body, maxRequestSize)
+		}
+
+		if r.Method == "POST" {
+			sent := r.Header.Get(csrfHeader)
+			if sent == "" {
+				if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+					r.ParseMultipartForm(1 << 20)
+				}
+				sent = r.PostFormValue(csrfField)
+			}
+
+			if token == "" || subtle.ConstantTimeCompare([]byte(sent), []byte(token)) != 1 {
+				http.Error(w, "Invalid or missing CSRF token", http.StatusForbidden)
+				return
+			}
+		}
+
+		if token == "" {
+			var err error
+			if token, err = setCSRFCookie(w, r); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), csrfKey, token)))
+	})
+}
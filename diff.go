@@ -0,0 +1,283 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	diffContext = 3
+
+	// Past this number of edits, all the lines between the common prefix and
+	// suffix are reported as replaced
+	diffMaxEdits = 1000
+)
+
+type diffOp int
+
+const (
+	diffEqual diffOp = iota
+	diffDelete
+	diffInsert
+)
+
+type diffLine struct {
+	Op   diffOp
+	Text string
+}
+
+type diffHunk struct {
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	Lines    []diffLine
+}
+
+// diffLines returns the edit script turning a into b using Myers' algorithm.
+func diffLines(a []string, b []string) []diffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]diffLine, 0, len(a)+len(b))
+	for _, text := range a[:prefix] {
+		lines = append(lines, diffLine{Op: diffEqual, Text: text})
+	}
+	lines = append(lines, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, text := range a[len(a)-suffix:] {
+		lines = append(lines, diffLine{Op: diffEqual, Text: text})
+	}
+	return lines
+}
+
+func myers(a []string, b []string) []diffLine {
+	n, m := len(a), len(b)
+	max := n + m
+	if max > 2*diffMaxEdits {
+		max = 2 * diffMaxEdits
+	}
+
+	// trace[d] holds the furthest reaching x for each diagonal k in
+	// [-d, d] at the end of round d.
+	trace := make([][]int, 0)
+	v := map[int]int{1: 0}
+
+	for d := 0; d <= max; d++ {
+		row := make([]int, 2*d+1)
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[k-1] < v[k+1]) {
+				x = v[k+1]
+			} else {
+				x = v[k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[k] = x
+			row[k+d] = x
+
+			if x >= n && y >= m {
+				trace = append(trace, row)
+				return myersBacktrack(trace, a, b)
+			}
+		}
+		trace = append(trace, row)
+	}
+
+	lines := make([]diffLine, 0, n+m)
+	for _, text := range a {
+		lines = append(lines, diffLine{Op: diffDelete, Text: text})
+	}
+	for _, text := range b {
+		lines = append(lines, diffLine{Op: diffInsert, Text: text})
+	}
+	return lines
+}
+
+func myersBacktrack(trace [][]int, a []string, b []string) []diffLine {
+	x, y := len(a), len(b)
+	reversed := make([]diffLine, 0, x+y)
+
+	for d := len(trace) - 1; d > 0; d-- {
+		prev := trace[d-1]
+		at := func(k int) int {
+			return prev[k+d-1]
+		}
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			reversed = append(reversed, diffLine{Op: diffEqual, Text: a[x-1]})
+			x--
+			y--
+		}
+		if x == prevX {
+			reversed = append(reversed, diffLine{Op: diffInsert, Text: b[y-1]})
+		} else {
+			reversed = append(reversed, diffLine{Op: diffDelete, Text: a[x-1]})
+		}
+		x, y = prevX, prevY
+	}
+
+	for x > 0 && y > 0 {
+		reversed = append(reversed, diffLine{Op: diffEqual, Text: a[x-1]})
+		x--
+		y--
+	}
+
+	lines := make([]diffLine, len(reversed))
+	for i, line := range reversed {
+		lines[len(reversed)-1-i] = line
+	}
+	return lines
+}
+
+// diffHunks groups an edit script into hunks surrounded by context lines.
+func diffHunks(lines []diffLine, context int) []diffHunk {
+	hunks := make([]diffHunk, 0)
+
+	var hunk *diffHunk
+	oldLine, newLine := 1, 1
+	lastChange := -1
+
+	for i, line := range lines {
+		if line.Op != diffEqual {
+			if hunk == nil || i-lastChange > 2*context {
+				if hunk != nil {
+					hunks = append(hunks, closeHunk(*hunk, lines, lastChange, context))
+				}
+				start := i - context
+				if start < 0 {
+					start = 0
+				}
+				hunk = &diffHunk{
+					OldStart: oldLine - (i - start),
+					NewStart: newLine - (i - start),
+				}
+				hunk.Lines = append(hunk.Lines, lines[start:i]...)
+			} else {
+				hunk.Lines = append(hunk.Lines, lines[lastChange+1:i]...)
+			}
+			hunk.Lines = append(hunk.Lines, line)
+			lastChange = i
+		}
+
+		switch line.Op {
+		case diffEqual:
+			oldLine++
+			newLine++
+		case diffDelete:
+			oldLine++
+		case diffInsert:
+			newLine++
+		}
+	}
+
+	if hunk != nil {
+		hunks = append(hunks, closeHunk(*hunk, lines, lastChange, context))
+	}
+
+	for i := range hunks {
+		for _, line := range hunks[i].Lines {
+			if line.Op != diffInsert {
+				hunks[i].OldLines++
+			}
+			if line.Op != diffDelete {
+				hunks[i].NewLines++
+			}
+		}
+	}
+
+	return hunks
+}
+
+// closeHunk appends the context lines following the last change of a hunk.
+func closeHunk(hunk diffHunk, lines []diffLine, lastChange int, context int) diffHunk {
+	end := lastChange + 1 + context
+	if end > len(lines) {
+		end = len(lines)
+	}
+	hunk.Lines = append(hunk.Lines, lines[lastChange+1:end]...)
+	return hunk
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// PageDiff holds the differences between two versions of a page.
+type PageDiff struct {
+	FromName string
+	ToName   string
+	Hunks    []diffHunk
+}
+
+func newPageDiff(fromName string, toName string, from string, to string) *PageDiff {
+	return &PageDiff{
+		FromName: fromName,
+		ToName:   toName,
+		Hunks:    diffHunks(diffLines(splitLines(from), splitLines(to)), diffContext),
+	}
+}
+
+// header returns the range line introducing the hunk in a unified diff.
+func (h diffHunk) header() string {
+	oldStart, newStart := h.OldStart, h.NewStart
+	if h.OldLines == 0 {
+		oldStart--
+	}
+	if h.NewLines == 0 {
+		newStart--
+	}
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", oldStart, h.OldLines, newStart, h.NewLines)
+}
+
+// Unified formats the diff like diff -u.
+func (d *PageDiff) Unified() []byte {
+	if len(d.Hunks) == 0 {
+		return nil
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", d.FromName, d.ToName)
+	for _, hunk := range d.Hunks {
+		out.WriteString(hunk.header())
+		out.WriteString("\n")
+		for _, line := range hunk.Lines {
+			switch line.Op {
+			case diffEqual:
+				out.WriteString(" ")
+			case diffDelete:
+				out.WriteString("-")
+			case diffInsert:
+				out.WriteString("+")
+			}
+			out.WriteString(line.Text)
+			out.WriteString("\n")
+		}
+	}
+	return out.Bytes()
+}
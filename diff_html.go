@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"html/template"
+	"regexp"
+)
+
+const (
+	diffViewUnified    = "unified"
+	diffViewSideBySide = "side-by-side"
+)
+
+var wordPattern = regexp.MustCompile(`\w+|\s+|[^\w\s]`)
+
+// diffCell is a line of a hunk ready to be displayed. A zero line number
+// means the line is missing from that side.
+type diffCell struct {
+	Op      diffOp
+	OldLine int
+	NewLine int
+	HTML    string
+}
+
+// diffCells numbers the lines of hunk and highlights the words changed
+// between the deleted lines and the inserted lines replacing them.
+func diffCells(hunk diffHunk) []diffCell {
+	cells := make([]diffCell, len(hunk.Lines))
+
+	oldLine, newLine := hunk.OldStart, hunk.NewStart
+	for i, line := range hunk.Lines {
+		cells[i] = diffCell{Op: line.Op, HTML: html.EscapeString(line.Text)}
+		if line.Op != diffInsert {
+			cells[i].OldLine = oldLine
+			oldLine++
+		}
+		if line.Op != diffDelete {
+			cells[i].NewLine = newLine
+			newLine++
+		}
+	}
+
+	for _, block := range changeBlocks(hunk.Lines) {
+		deleted, inserted := block[0], block[1]
+		for n := 0; n < len(deleted) && n < len(inserted); n++ {
+			i, j := deleted[n], inserted[n]
+			cells[i].HTML, cells[j].HTML = wordDiff(hunk.Lines[i].Text, hunk.Lines[j].Text)
+		}
+	}
+	return cells
+}
+
+// changeBlocks returns the indexes of the runs of deleted lines and of the
+// inserted lines following them.
+func changeBlocks(lines []diffLine) [][2][]int {
+	blocks := make([][2][]int, 0)
+	for i := 0; i < len(lines); {
+		if lines[i].Op == diffEqual {
+			i++
+			continue
+		}
+
+		var block [2][]int
+		for ; i < len(lines) && lines[i].Op == diffDelete; i++ {
+			block[0] = append(block[0], i)
+		}
+		for ; i < len(lines) && lines[i].Op == diffInsert; i++ {
+			block[1] = append(block[1], i)
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// wordDiff returns the escaped lines a and b with the words removed from a
+// and added to b highlighted.
+func wordDiff(a string, b string) (string, string) {
+	var oldOut, newOut bytes.Buffer
+	var oldChanged, newChanged bool
+
+	flush := func(out *bytes.Buffer, changed *bool, tag string) {
+		if *changed {
+			out.WriteString("</" + tag + ">")
+			*changed = false
+		}
+	}
+
+	for _, token := range diffLines(wordPattern.FindAllString(a, -1), wordPattern.FindAllString(b, -1)) {
+		text := html.EscapeString(token.Text)
+		switch token.Op {
+		case diffEqual:
+			flush(&oldOut, &oldChanged, "del")
+			flush(&newOut, &newChanged, "ins")
+			oldOut.WriteString(text)
+			newOut.WriteString(text)
+		case diffDelete:
+			if !oldChanged {
+				oldOut.WriteString("<del>")
+				oldChanged = true
+			}
+			oldOut.WriteString(text)
+		case diffInsert:
+			if !newChanged {
+				newOut.WriteString("<ins>")
+				newChanged = true
+			}
+			newOut.WriteString(text)
+		}
+	}
+	flush(&oldOut, &oldChanged, "del")
+	flush(&newOut, &newChanged, "ins")
+
+	return oldOut.String(), newOut.String()
+}
+
+var diffClasses = map[diffOp]string{
+	diffEqual:  "diff-equal",
+	diffDelete: "diff-delete",
+	diffInsert: "diff-insert",
+}
+
+var diffMarkers = map[diffOp]string{
+	diffEqual:  " ",
+	diffDelete: "-",
+	diffInsert: "+",
+}
+
+func lineNumber(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprint(n)
+}
+
+// renderDiff renders d as an HTML table in the given view, unified or side
+// by side. An empty diff renders as nothing.
+func renderDiff(d *PageDiff, view string) template.HTML {
+	if len(d.Hunks) == 0 {
+		return ""
+	}
+
+	var out bytes.Buffer
+	if view == diffViewSideBySide {
+		renderSideBySide(&out, d)
+	} else {
+		renderUnified(&out, d)
+	}
+	return template.HTML(out.String())
+}
+
+func renderUnified(out *bytes.Buffer, d *PageDiff) {
+	out.WriteString(`<table class="diff diff-unified">`)
+	fmt.Fprintf(out, `<thead><tr><th colspan="3">%s &rarr; %s</th></tr></thead>`,
+		html.EscapeString(d.FromName), html.EscapeString(d.ToName))
+	out.WriteString("<tbody>")
+
+	for _, hunk := range d.Hunks {
+		fmt.Fprintf(out, `<tr class="diff-hunk"><td colspan="3">%s</td></tr>`, html.EscapeString(hunk.header()))
+		for _, cell := range diffCells(hunk) {
+			fmt.Fprintf(out, `<tr class="%s"><td class="diff-number">%s</td><td class="diff-number">%s</td><td class="diff-text">%s%s</td></tr>`,
+				diffClasses[cell.Op], lineNumber(cell.OldLine), lineNumber(cell.NewLine), diffMarkers[cell.Op], cell.HTML)
+		}
+	}
+
+	out.WriteString("</tbody></table>")
+}
+
+func renderSideBySide(out *bytes.Buffer, d *PageDiff) {
+	out.WriteString(`<table class="diff diff-side-by-side">`)
+	fmt.Fprintf(out, `<thead><tr><th colspan="2">%s</th><th colspan="2">%s</th></tr></thead>`,
+		html.EscapeString(d.FromName), html.EscapeString(d.ToName))
+	out.WriteString("<tbody>")
+
+	side := func(cell *diffCell, line int) {
+		if cell == nil {
+			out.WriteString(`<td class="diff-number diff-empty"></td><td class="diff-text diff-empty"></td>`)
+			return
+		}
+		fmt.Fprintf(out, `<td class="diff-number %s">%s</td><td class="diff-text %s">%s</td>`,
+			diffClasses[cell.Op], lineNumber(line), diffClasses[cell.Op], cell.HTML)
+	}
+
+	for _, hunk := range d.Hunks {
+		fmt.Fprintf(out, `<tr class="diff-hunk"><td colspan="4">%s</td></tr>`, html.EscapeString(hunk.header()))
+
+		cells := diffCells(hunk)
+		for i := 0; i < len(cells); {
+			if cells[i].Op == diffEqual {
+				out.WriteString("<tr>")
+				side(&cells[i], cells[i].OldLine)
+				side(&cells[i], cells[i].NewLine)
+				out.WriteString("</tr>")
+				i++
+				continue
+			}
+
+			// Show the deleted lines next to the inserted ones
+			var deleted, inserted []*diffCell
+			for ; i < len(cells) && cells[i].Op == diffDelete; i++ {
+				deleted = append(deleted, &cells[i])
+			}
+			for ; i < len(cells) && cells[i].Op == diffInsert; i++ {
+				inserted = append(inserted, &cells[i])
+			}
+
+			for n := 0; n < len(deleted) || n < len(inserted); n++ {
+				out.WriteString("<tr>")
+				if n < len(deleted) {
+					side(deleted[n], deleted[n].OldLine)
+				} else {
+					side(nil, 0)
+				}
+				if n < len(inserted) {
+					side(inserted[n], inserted[n].NewLine)
+				} else {
+					side(nil, 0)
+				}
+				out.WriteString("</tr>")
+			}
+		}
+	}
+
+	out.WriteString("</tbody></table>")
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"encoding/xml"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	feedAtom = "atom"
+	feedRSS  = "rss"
+
+	// feedSize is the number of entries of the feeds.
+	feedSize = 20
+)
+
+// feedEntry is a change shown in a feed, whatever its format. Its link to the
+// diff also identifies it.
+type feedEntry struct {
+	Title   string
+	Link    string
+	Author  string
+	Email   string
+	Date    time.Time
+	Content string
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomPerson struct {
+	Name  string `xml:"name"`
+	Email string `xml:"email,omitempty"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Author  atomPerson  `xml:"author"`
+	Content atomContent `xml:"content"`
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title       string    `xml:"title"`
+	Link        string    `xml:"link"`
+	Description string    `xml:"description"`
+	Items       []rssItem `xml:"item"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Description string  `xml:"description"`
+	Author      string  `xml:"author,omitempty"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+// baseURL returns the root URL of the wiki as reached by r.
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// writeFeed writes entries, most recent first, as a feed in format. link is
+// the page the feed follows.
+func writeFeed(w http.ResponseWriter, r *http.Request, format string, title string, link string, entries []feedEntry) {
+	var feed interface{}
+	contentType := "application/atom+xml; charset=utf-8"
+
+	switch format {
+	case feedAtom:
+		updated := time.Now()
+		if len(entries) > 0 {
+			updated = entries[0].Date
+		}
+
+		atom := atomFeed{
+			ID:      baseURL(r) + r.URL.RequestURI(),
+			Title:   title,
+			Updated: updated.Format(time.RFC3339),
+			Links: []atomLink{
+				{Href: link, Rel: "alternate"},
+				{Href: baseURL(r) + r.URL.RequestURI(), Rel: "self"},
+			},
+		}
+		for _, entry := range entries {
+			atom.Entries = append(atom.Entries, atomEntry{
+				ID:      entry.Link,
+				Title:   entry.Title,
+				Updated: entry.Date.Format(time.RFC3339),
+				Link:    atomLink{Href: entry.Link, Rel: "alternate"},
+				Author:  atomPerson{Name: entry.Author, Email: entry.Email},
+				Content: atomContent{Type: "html", Body: entry.Content},
+			})
+		}
+		feed = atom
+
+	case feedRSS:
+		contentType = "application/rss+xml; charset=utf-8"
+		rss := rssFeed{
+			Version: "2.0",
+			Channel: rssChannel{Title: title, Link: link, Description: title},
+		}
+		for _, entry := range entries {
+			item := rssItem{
+				Title:       entry.Title,
+				Link:        entry.Link,
+				Description: entry.Content,
+				GUID:        rssGUID{IsPermaLink: true, Value: entry.Link},
+				PubDate:     entry.Date.Format(time.RFC1123Z),
+			}
+			// RSS authors are email addresses
+			if entry.Email != "" {
+				item.Author = entry.Email + " (" + entry.Author + ")"
+			}
+			rss.Channel.Items = append(rss.Channel.Items, item)
+		}
+		feed = rss
+
+	default:
+		http.Error(w, "Unknown feed format: "+format, http.StatusNotFound)
+		return
+	}
+
+	content, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Write([]byte(xml.Header))
+	w.Write(content)
+}
+
+// feedEntry returns the entry showing the changes made to a page by commit
+// since from.
+func (app AppContext) feedEntry(r *http.Request, commit Commit, title string, fromTitle string, toTitle string, from string, kind string) (feedEntry, error) {
+	diff, err := app.Storage.DiffPages(fromTitle, from, toTitle, commit.ID)
+	if err != nil {
+		return feedEntry{}, err
+	}
+
+	date, _ := time.Parse(gitDateFormat, commit.Date)
+	entry := feedEntry{
+		Title:   title + " (" + kind + "): " + commit.Message,
+		Link:    baseURL(r) + "/" + escapeTitle(title) + "?action=diff&from=" + from + "&to=" + commit.ID,
+		Author:  commit.AuthorName,
+		Email:   commit.AuthorEmail,
+		Date:    date,
+		Content: string(renderDiff(diff, diffViewUnified)),
+	}
+	if entry.Author == "" {
+		entry.Author = "unknown"
+	}
+	return entry, nil
+}
+
+func (app AppContext) historyFeedHandler(w http.ResponseWriter, r *http.Request) {
+	title := normalizePath(mux.Vars(r)["title"])
+	if !app.authorize(w, r, title, rightRead) {
+		return
+	}
+
+	commits, err := app.Storage.History(title)
+	if err != nil {
+		http.Error(w, err.Error(), statusOf(err))
+		return
+	}
+
+	entries := make([]feedEntry, 0, feedSize)
+	for i := 0; i < len(commits) && i < feedSize; i++ {
+		from, kind := "", changeAdd
+		if i+1 < len(commits) {
+			from = commits[i+1].ID
+			if !commits[i+1].Delete {
+				kind = changeModify
+			}
+		}
+		if commits[i].Delete {
+			kind = changeDelete
+		}
+
+		entry, err := app.feedEntry(r, commits[i], title, titleAt(commits, title, from), titleAt(commits, title, commits[i].ID), from, kind)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		entries = append(entries, entry)
+	}
+
+	writeFeed(w, r, mux.Vars(r)["format"], title+" history", baseURL(r)+"/"+escapeTitle(title)+"?action=history", entries)
+}
+
+func (app AppContext) recentChangesFeedHandler(w http.ResponseWriter, r *http.Request) {
+	changes, err := app.Storage.RecentChanges(ChangeFilter{
+		Pages: func(title string) bool {
+			return app.ACL.Allowed(currentUser(r), title, rightRead)
+		},
+		Limit: feedSize,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	entries := make([]feedEntry, 0, len(changes))
+	for _, change := range changes {
+		fromTitle := change.Title
+		if change.RenamedFrom != "" {
+			fromTitle = change.RenamedFrom
+		}
+		entry, err := app.feedEntry(r, change.Commit, change.Title, fromTitle, change.Title, change.Parent, change.Kind)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		entries = append(entries, entry)
+	}
+
+	writeFeed(w, r, mux.Vars(r)["format"], "Recent Changes", baseURL(r)+"/_/recent-changes", entries)
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	gitModeFile    = "100644"
+	gitModeExec    = "100755"
+	gitModeTree    = "40000"
+	gitModeSymlink = "120000"
+)
+
+const gitDateFormat = "Mon Jan 2 15:04:05 2006 -0700"
+
+var errObjectNotFound = errors.New("Object not found")
+
+type gitHash [20]byte
+
+var zeroHash gitHash
+
+func (h gitHash) String() string {
+	return hex.EncodeToString(h[:])
+}
+
+func (h gitHash) IsZero() bool {
+	return h == zeroHash
+}
+
+func parseHash(s string) (gitHash, error) {
+	var h gitHash
+	if len(s) != 40 {
+		return h, errors.New("Invalid object name " + s)
+	}
+	if _, err := hex.Decode(h[:], []byte(s)); err != nil {
+		return h, errors.New("Invalid object name " + s)
+	}
+	return h, nil
+}
+
+func hashObject(objType string, data []byte) gitHash {
+	h := sha1.New()
+	fmt.Fprintf(h, "%s %d\x00", objType, len(data))
+	h.Write(data)
+	var id gitHash
+	copy(id[:], h.Sum(nil))
+	return id
+}
+
+type gitSignature struct {
+	Name  string
+	Email string
+	When  time.Time
+}
+
+func (s gitSignature) String() string {
+	return fmt.Sprintf("%s <%s> %d %s", s.Name, s.Email, s.When.Unix(), s.When.Format("-0700"))
+}
+
+func parseSignature(s string) gitSignature {
+	var sig gitSignature
+
+	lt := strings.Index(s, "<")
+	gt := strings.LastIndex(s, ">")
+	if lt < 0 || gt < lt {
+		sig.Name = s
+		return sig
+	}
+	sig.Name = strings.TrimSpace(s[:lt])
+	sig.Email = s[lt+1 : gt]
+
+	fields := strings.Fields(s[gt+1:])
+	if len(fields) < 1 {
+		return sig
+	}
+	seconds, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return sig
+	}
+
+	location := time.UTC
+	if len(fields) > 1 && len(fields[1]) == 5 {
+		hours, errH := strconv.Atoi(fields[1][1:3])
+		minutes, errM := strconv.Atoi(fields[1][3:5])
+		if errH == nil && errM == nil {
+			offset := hours*3600 + minutes*60
+			if fields[1][0] == '-' {
+				offset = -offset
+			}
+			location = time.FixedZone("", offset)
+		}
+	}
+	sig.When = time.Unix(seconds, 0).In(location)
+	return sig
+}
+
+type gitCommit struct {
+	ID        gitHash
+	Tree      gitHash
+	Parents   []gitHash
+	Author    gitSignature
+	Committer gitSignature
+	Message   string
+}
+
+func parseCommit(id gitHash, data []byte) (*gitCommit, error) {
+	commit := &gitCommit{ID: id}
+
+	headers := data
+	if i := bytes.Index(data, []byte("\n\n")); i >= 0 {
+		headers = data[:i]
+		commit.Message = string(data[i+2:])
+	}
+
+	for _, line := range strings.Split(string(headers), "\n") {
+		if strings.HasPrefix(line, " ") {
+			// Continuation of a multi-line header such as gpgsig
+			continue
+		}
+		parts := strings.SplitN(line, " ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		switch parts[0] {
+		case "tree":
+			h, err := parseHash(parts[1])
+			if err != nil {
+				return nil, err
+			}
+			commit.Tree = h
+		case "parent":
+			h, err := parseHash(parts[1])
+			if err != nil {
+				return nil, err
+			}
+			commit.Parents = append(commit.Parents, h)
+		case "author":
+			commit.Author = parseSignature(parts[1])
+		case "committer":
+			commit.Committer = parseSignature(parts[1])
+		}
+	}
+
+	return commit, nil
+}
+
+func (c *gitCommit) encode() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "tree %s\n", c.Tree)
+	for _, parent := range c.Parents {
+		fmt.Fprintf(&buf, "parent %s\n", parent)
+	}
+	fmt.Fprintf(&buf, "author %s\n", c.Author)
+	fmt.Fprintf(&buf, "committer %s\n", c.Committer)
+	buf.WriteString("\n")
+	buf.WriteString(c.Message)
+	return buf.Bytes()
+}
+
+// cleanCommitMessage normalizes a commit message the way git commit does:
+// trailing whitespace is removed and the message ends with a newline.
+func cleanCommitMessage(message string) string {
+	lines := strings.Split(strings.Replace(message, "\r\n", "\n", -1), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	message = strings.Trim(strings.Join(lines, "\n"), "\n")
+	return message + "\n"
+}
+
+type gitTreeEntry struct {
+	Mode string
+	Name string
+	ID   gitHash
+}
+
+func (e gitTreeEntry) IsTree() bool {
+	return e.Mode == gitModeTree
+}
+
+func parseTree(data []byte) ([]gitTreeEntry, error) {
+	entries := make([]gitTreeEntry, 0)
+	for len(data) > 0 {
+		space := bytes.IndexByte(data, ' ')
+		if space < 0 {
+			return nil, errors.New("Malformed tree entry")
+		}
+		nul := bytes.IndexByte(data[space:], 0)
+		if nul < 0 || space+nul+21 > len(data) {
+			return nil, errors.New("Malformed tree entry")
+		}
+		nul += space
+
+		var entry gitTreeEntry
+		entry.Mode = string(data[:space])
+		entry.Name = string(data[space+1 : nul])
+		copy(entry.ID[:], data[nul+1:nul+21])
+		entries = append(entries, entry)
+
+		data = data[nul+21:]
+	}
+	return entries, nil
+}
+
+func encodeTree(entries []gitTreeEntry) []byte {
+	sorted := make([]gitTreeEntry, len(entries))
+	copy(sorted, entries)
+
+	// Git sorts trees as if their name ended with a slash
+	sortName := func(e gitTreeEntry) string {
+		if e.IsTree() {
+			return e.Name + "/"
+		}
+		return e.Name
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sortName(sorted[i]) < sortName(sorted[j])
+	})
+
+	var buf bytes.Buffer
+	for _, entry := range sorted {
+		buf.WriteString(entry.Mode)
+		buf.WriteString(" ")
+		buf.WriteString(entry.Name)
+		buf.WriteByte(0)
+		buf.Write(entry.ID[:])
+	}
+	return buf.Bytes()
+}
+
+func looseObjectPath(objectsDir string, id gitHash) string {
+	s := id.String()
+	return filepath.Join(objectsDir, s[:2], s[2:])
+}
+
+func readLooseObject(objectsDir string, id gitHash) (string, []byte, error) {
+	file, err := os.Open(looseObjectPath(objectsDir, id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil, errObjectNotFound
+		}
+		return "", nil, err
+	}
+	defer file.Close()
+
+	zr, err := zlib.NewReader(file)
+	if err != nil {
+		return "", nil, err
+	}
+	defer zr.Close()
+
+	raw, err := ioutil.ReadAll(zr)
+	if err != nil {
+		return "", nil, err
+	}
+
+	nul := bytes.IndexByte(raw, 0)
+	if nul < 0 {
+		return "", nil, errors.New("Malformed object " + id.String())
+	}
+	header := strings.SplitN(string(raw[:nul]), " ", 2)
+	if len(header) != 2 {
+		return "", nil, errors.New("Malformed object " + id.String())
+	}
+	return header[0], raw[nul+1:], nil
+}
+
+func writeLooseObject(objectsDir string, objType string, data []byte) (gitHash, error) {
+	id := hashObject(objType, data)
+	filename := looseObjectPath(objectsDir, id)
+
+	if _, err := os.Stat(filename); err == nil {
+		return id, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0775); err != nil {
+		return id, err
+	}
+
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	fmt.Fprintf(zw, "%s %d\x00", objType, len(data))
+	zw.Write(data)
+	if err := zw.Close(); err != nil {
+		return id, err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(filename), "tmp_obj_")
+	if err != nil {
+		return id, err
+	}
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return id, err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return id, err
+	}
+	os.Chmod(tmp.Name(), 0444)
+
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		os.Remove(tmp.Name())
+		return id, err
+	}
+	return id, nil
+}
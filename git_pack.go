@@ -0,0 +1,295 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	packObjCommit   = 1
+	packObjTree     = 2
+	packObjBlob     = 3
+	packObjTag      = 4
+	packObjOfsDelta = 6
+	packObjRefDelta = 7
+)
+
+var packTypeNames = map[int]string{
+	packObjCommit: "commit",
+	packObjTree:   "tree",
+	packObjBlob:   "blob",
+	packObjTag:    "tag",
+}
+
+// gitPack reads objects from a pack file using its version 2 index.
+type gitPack struct {
+	name    string
+	file    *os.File
+	fanout  [256]uint32
+	ids     []gitHash
+	offsets []int64
+}
+
+func openPack(idxPath string) (*gitPack, error) {
+	idx, err := ioutil.ReadFile(idxPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(idx) < 8+256*4 || !bytes.Equal(idx[:4], []byte{0xff, 't', 'O', 'c'}) {
+		return nil, errors.New("Unsupported pack index " + idxPath)
+	}
+	if version := binary.BigEndian.Uint32(idx[4:8]); version != 2 {
+		return nil, errors.New("Unsupported pack index version " + idxPath)
+	}
+
+	p := &gitPack{name: idxPath}
+	for i := 0; i < 256; i++ {
+		p.fanout[i] = binary.BigEndian.Uint32(idx[8+i*4:])
+	}
+
+	n := int(p.fanout[255])
+	idsStart := 8 + 256*4
+	crcStart := idsStart + n*20
+	offsetsStart := crcStart + n*4
+	largeStart := offsetsStart + n*4
+	if len(idx) < largeStart {
+		return nil, errors.New("Truncated pack index " + idxPath)
+	}
+
+	p.ids = make([]gitHash, n)
+	p.offsets = make([]int64, n)
+	for i := 0; i < n; i++ {
+		copy(p.ids[i][:], idx[idsStart+i*20:])
+
+		offset := binary.BigEndian.Uint32(idx[offsetsStart+i*4:])
+		if offset&0x80000000 != 0 {
+			large := largeStart + int(offset&0x7fffffff)*8
+			if len(idx) < large+8 {
+				return nil, errors.New("Truncated pack index " + idxPath)
+			}
+			p.offsets[i] = int64(binary.BigEndian.Uint64(idx[large:]))
+		} else {
+			p.offsets[i] = int64(offset)
+		}
+	}
+
+	p.file, err = os.Open(strings.TrimSuffix(idxPath, ".idx") + ".pack")
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// find returns the index of the first object id greater than or equal to
+// the given prefix.
+func (p *gitPack) find(prefix []byte) int {
+	lo := 0
+	if prefix[0] > 0 {
+		lo = int(p.fanout[prefix[0]-1])
+	}
+	hi := int(p.fanout[prefix[0]])
+
+	return lo + sort.Search(hi-lo, func(i int) bool {
+		return bytes.Compare(p.ids[lo+i][:], prefix) >= 0
+	})
+}
+
+func (p *gitPack) offset(id gitHash) (int64, bool) {
+	i := p.find(id[:])
+	if i < len(p.ids) && p.ids[i] == id {
+		return p.offsets[i], true
+	}
+	return 0, false
+}
+
+// matchPrefix returns the ids starting with the given hexadecimal prefix.
+func (p *gitPack) matchPrefix(prefix string) []gitHash {
+	raw, err := hex.DecodeString(prefix[:len(prefix)/2*2])
+	if err != nil || len(raw) == 0 {
+		return nil
+	}
+
+	matches := make([]gitHash, 0)
+	for i := p.find(raw); i < len(p.ids) && bytes.HasPrefix(p.ids[i][:], raw); i++ {
+		if strings.HasPrefix(p.ids[i].String(), prefix) {
+			matches = append(matches, p.ids[i])
+		}
+	}
+	return matches
+}
+
+func (p *gitPack) readObject(id gitHash, resolve func(gitHash) (string, []byte, error)) (string, []byte, error) {
+	offset, ok := p.offset(id)
+	if !ok {
+		return "", nil, errObjectNotFound
+	}
+	return p.readAt(offset, resolve)
+}
+
+func (p *gitPack) readAt(offset int64, resolve func(gitHash) (string, []byte, error)) (string, []byte, error) {
+	r := bufio.NewReader(io.NewSectionReader(p.file, offset, 1<<62))
+
+	c, err := r.ReadByte()
+	if err != nil {
+		return "", nil, err
+	}
+	objType := int(c>>4) & 7
+	size := int64(c & 0x0f)
+	shift := uint(4)
+	for c&0x80 != 0 {
+		if c, err = r.ReadByte(); err != nil {
+			return "", nil, err
+		}
+		size |= int64(c&0x7f) << shift
+		shift += 7
+	}
+
+	switch objType {
+	case packObjCommit, packObjTree, packObjBlob, packObjTag:
+		data, err := inflate(r, size)
+		if err != nil {
+			return "", nil, err
+		}
+		return packTypeNames[objType], data, nil
+
+	case packObjOfsDelta:
+		c, err := r.ReadByte()
+		if err != nil {
+			return "", nil, err
+		}
+		baseOffset := int64(c & 0x7f)
+		for c&0x80 != 0 {
+			if c, err = r.ReadByte(); err != nil {
+				return "", nil, err
+			}
+			baseOffset = ((baseOffset + 1) << 7) | int64(c&0x7f)
+		}
+
+		delta, err := inflate(r, size)
+		if err != nil {
+			return "", nil, err
+		}
+		baseType, base, err := p.readAt(offset-baseOffset, resolve)
+		if err != nil {
+			return "", nil, err
+		}
+		data, err := applyDelta(base, delta)
+		return baseType, data, err
+
+	case packObjRefDelta:
+		var baseID gitHash
+		if _, err := io.ReadFull(r, baseID[:]); err != nil {
+			return "", nil, err
+		}
+
+		delta, err := inflate(r, size)
+		if err != nil {
+			return "", nil, err
+		}
+		baseType, base, err := resolve(baseID)
+		if err != nil {
+			return "", nil, err
+		}
+		data, err := applyDelta(base, delta)
+		return baseType, data, err
+	}
+
+	return "", nil, errors.New("Unknown pack object type")
+}
+
+func inflate(r io.Reader, size int64) ([]byte, error) {
+	zr, err := zlib.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(zr, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func readDeltaSize(delta []byte) (int64, []byte) {
+	var size int64
+	var shift uint
+	for len(delta) > 0 {
+		c := delta[0]
+		delta = delta[1:]
+		size |= int64(c&0x7f) << shift
+		shift += 7
+		if c&0x80 == 0 {
+			break
+		}
+	}
+	return size, delta
+}
+
+func applyDelta(base []byte, delta []byte) ([]byte, error) {
+	errCorrupt := errors.New("Corrupt delta")
+
+	baseSize, delta := readDeltaSize(delta)
+	if baseSize != int64(len(base)) {
+		return nil, errCorrupt
+	}
+	resultSize, delta := readDeltaSize(delta)
+
+	result := make([]byte, 0, resultSize)
+	for len(delta) > 0 {
+		op := delta[0]
+		delta = delta[1:]
+
+		if op&0x80 != 0 {
+			var offset, size int64
+			for i := uint(0); i < 4; i++ {
+				if op&(1<<i) != 0 {
+					if len(delta) == 0 {
+						return nil, errCorrupt
+					}
+					offset |= int64(delta[0]) << (8 * i)
+					delta = delta[1:]
+				}
+			}
+			for i := uint(0); i < 3; i++ {
+				if op&(0x10<<i) != 0 {
+					if len(delta) == 0 {
+						return nil, errCorrupt
+					}
+					size |= int64(delta[0]) << (8 * i)
+					delta = delta[1:]
+				}
+			}
+			if size == 0 {
+				size = 0x10000
+			}
+			if offset+size > int64(len(base)) {
+				return nil, errCorrupt
+			}
+			result = append(result, base[offset:offset+size]...)
+		} else if op != 0 {
+			if int(op) > len(delta) {
+				return nil, errCorrupt
+			}
+			result = append(result, delta[:op]...)
+			delta = delta[op:]
+		} else {
+			return nil, errCorrupt
+		}
+	}
+
+	if int64(len(result)) != resultSize {
+		return nil, errCorrupt
+	}
+	return result, nil
+}
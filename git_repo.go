@@ -1,30 +1,1290 @@
 package main
 
 import (
-	"io"
-	"os/exec"
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
+const (
+	gitObjectCacheSize  = 4096
+	gitIndexLockTimeout = time.Second // for another writer, such as git
+)
+
+var (
+	errPathNotFound = errors.New("Path not found")
+	errRefChanged   = errors.New("Reference changed concurrently")
+	errIndexLocked  = errors.New("Unable to lock the index: index.lock exists")
+)
+
+// GitRepo reads and writes a git repository without the git binary.
 type GitRepo struct {
 	Path string
+
+	// Ignore lists the top level directories of the work tree that are not
+	// part of the repository.
+	Ignore []string
+
+	mu          sync.RWMutex
+	packs       []*gitPack
+	packsLoaded bool
+	cache       map[gitHash]gitCachedObject
+}
+
+type gitCachedObject struct {
+	objType string
+	data    []byte
+}
+
+func (r *GitRepo) gitDir() string {
+	return filepath.Join(r.Path, ".git")
+}
+
+func (r *GitRepo) objectsDir() string {
+	return filepath.Join(r.gitDir(), "objects")
+}
+
+// Init creates an empty repository unless one already exists.
+func (r *GitRepo) Init() error {
+	if _, err := os.Stat(filepath.Join(r.gitDir(), "HEAD")); err == nil {
+		return r.writeExclude()
+	}
+
+	for _, dir := range []string{"objects/info", "objects/pack", "refs/heads", "refs/tags"} {
+		if err := os.MkdirAll(filepath.Join(r.gitDir(), dir), 0775); err != nil {
+			return err
+		}
+	}
+
+	config := "[core]\n" +
+		"\trepositoryformatversion = 0\n" +
+		"\tfilemode = true\n" +
+		"\tbare = false\n" +
+		"\tlogallrefupdates = true\n"
+	if err := ioutil.WriteFile(filepath.Join(r.gitDir(), "config"), []byte(config), 0664); err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(r.gitDir(), "HEAD"), []byte("ref: refs/heads/master\n"), 0664); err != nil {
+		return err
+	}
+	return r.writeExclude()
+}
+
+// writeExclude adds the ignored directories to .git/info/exclude so that git
+// ignores them too.
+func (r *GitRepo) writeExclude() error {
+	filename := filepath.Join(r.gitDir(), "info", "exclude")
+	content, err := ioutil.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	patterns := make(map[string]bool)
+	for _, line := range strings.Split(string(content), "\n") {
+		patterns[strings.TrimSpace(line)] = true
+	}
+
+	updated := string(content)
+	for _, dir := range r.Ignore {
+		pattern := "/" + dir + "/"
+		if patterns[pattern] {
+			continue
+		}
+		if updated != "" && !strings.HasSuffix(updated, "\n") {
+			updated += "\n"
+		}
+		updated += pattern + "\n"
+	}
+	if updated == string(content) {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0775); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, []byte(updated), 0664)
+}
+
+// loadPacks opens the pack files added since the last call. Packs already
+// open are kept as concurrent reads may be using them.
+func (r *GitRepo) loadPacks() error {
+	filenames, err := filepath.Glob(filepath.Join(r.objectsDir(), "pack", "pack-*.idx"))
+	if err != nil {
+		return err
+	}
+
+	opened := make(map[string]bool)
+	for _, p := range r.packs {
+		opened[p.name] = true
+	}
+
+	packs := r.packs[:len(r.packs):len(r.packs)]
+	for _, filename := range filenames {
+		if opened[filename] {
+			continue
+		}
+		p, err := openPack(filename)
+		if err != nil {
+			return err
+		}
+		packs = append(packs, p)
+	}
+	r.packs = packs
+	r.packsLoaded = true
+	return nil
+}
+
+func (r *GitRepo) ReadObject(id gitHash) (string, []byte, error) {
+	r.mu.RLock()
+	cached, ok := r.cache[id]
+	r.mu.RUnlock()
+	if ok {
+		return cached.objType, cached.data, nil
+	}
+
+	objType, data, err := r.readObject(id, true)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if objType == "commit" || objType == "tree" {
+		r.mu.Lock()
+		if r.cache == nil || len(r.cache) >= gitObjectCacheSize {
+			r.cache = make(map[gitHash]gitCachedObject)
+		}
+		r.cache[id] = gitCachedObject{objType: objType, data: data}
+		r.mu.Unlock()
+	}
+	return objType, data, nil
+}
+
+func (r *GitRepo) readObject(id gitHash, retry bool) (string, []byte, error) {
+	objType, data, err := readLooseObject(r.objectsDir(), id)
+	if err != errObjectNotFound {
+		return objType, data, err
+	}
+
+	r.mu.Lock()
+	if !r.packsLoaded {
+		if err := r.loadPacks(); err != nil {
+			r.mu.Unlock()
+			return "", nil, err
+		}
+	}
+	packs := r.packs
+	r.mu.Unlock()
+
+	for _, p := range packs {
+		objType, data, err := p.readObject(id, r.ReadObject)
+		if err != errObjectNotFound {
+			return objType, data, err
+		}
+	}
+
+	if retry {
+		// The repository may have been repacked in the meantime
+		r.mu.Lock()
+		err := r.loadPacks()
+		r.mu.Unlock()
+		if err != nil {
+			return "", nil, err
+		}
+		return r.readObject(id, false)
+	}
+
+	return "", nil, errors.New("Object not found: " + id.String())
+}
+
+func (r *GitRepo) WriteObject(objType string, data []byte) (gitHash, error) {
+	return writeLooseObject(r.objectsDir(), objType, data)
+}
+
+func (r *GitRepo) readTyped(id gitHash, expected string) ([]byte, error) {
+	objType, data, err := r.ReadObject(id)
+	if err != nil {
+		return nil, err
+	}
+	if objType != expected {
+		return nil, errors.New("Object " + id.String() + " is a " + objType + ", not a " + expected)
+	}
+	return data, nil
+}
+
+func (r *GitRepo) ReadBlob(id gitHash) ([]byte, error) {
+	return r.readTyped(id, "blob")
+}
+
+func (r *GitRepo) ReadCommit(id gitHash) (*gitCommit, error) {
+	data, err := r.readTyped(id, "commit")
+	if err != nil {
+		return nil, err
+	}
+	return parseCommit(id, data)
+}
+
+func (r *GitRepo) ReadTree(id gitHash) ([]gitTreeEntry, error) {
+	data, err := r.readTyped(id, "tree")
+	if err != nil {
+		return nil, err
+	}
+	return parseTree(data)
+}
+
+// headRef returns the name of the reference HEAD points to.
+func (r *GitRepo) headRef() (string, error) {
+	content, err := ioutil.ReadFile(filepath.Join(r.gitDir(), "HEAD"))
+	if err != nil {
+		return "", err
+	}
+
+	line := strings.TrimSpace(string(content))
+	if !strings.HasPrefix(line, "ref:") {
+		return "", errors.New("HEAD is detached")
+	}
+	return strings.TrimSpace(strings.TrimPrefix(line, "ref:")), nil
+}
+
+func (r *GitRepo) readRef(name string) (gitHash, error) {
+	content, err := ioutil.ReadFile(filepath.Join(r.gitDir(), filepath.FromSlash(name)))
+	if err == nil {
+		line := strings.TrimSpace(string(content))
+		if strings.HasPrefix(line, "ref:") {
+			return r.readRef(strings.TrimSpace(strings.TrimPrefix(line, "ref:")))
+		}
+		return parseHash(line)
+	}
+	if !os.IsNotExist(err) {
+		return zeroHash, err
+	}
+
+	file, err := os.Open(filepath.Join(r.gitDir(), "packed-refs"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return zeroHash, errors.New("Unknown reference " + name)
+		}
+		return zeroHash, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "^") {
+			continue
+		}
+		parts := strings.SplitN(line, " ", 2)
+		if len(parts) == 2 && parts[1] == name {
+			return parseHash(parts[0])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return zeroHash, err
+	}
+	return zeroHash, errors.New("Unknown reference " + name)
+}
+
+// UpdateRef points the reference name to id if it still points to old. A
+// zero old id means the reference must not exist yet.
+func (r *GitRepo) UpdateRef(name string, old gitHash, id gitHash) error {
+	filename := filepath.Join(r.gitDir(), filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(filename), 0775); err != nil {
+		return err
+	}
+
+	lock, err := os.OpenFile(filename+".lock", os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0664)
+	if err != nil {
+		if os.IsExist(err) {
+			return errors.New("Unable to lock " + name + ": " + filename + ".lock exists")
+		}
+		return err
+	}
+
+	current, err := r.readRef(name)
+	if err != nil {
+		current = zeroHash
+	}
+	if current != old {
+		lock.Close()
+		os.Remove(lock.Name())
+		return errRefChanged
+	}
+
+	if _, err := lock.WriteString(id.String() + "\n"); err != nil {
+		lock.Close()
+		os.Remove(lock.Name())
+		return err
+	}
+	if err := lock.Close(); err != nil {
+		os.Remove(lock.Name())
+		return err
+	}
+	return os.Rename(lock.Name(), filename)
+}
+
+// ResolveRevision returns the commit designated by a revision: a reference
+// name, a full or abbreviated commit id, optionally followed by ~N or ^N.
+func (r *GitRepo) ResolveRevision(revision string) (gitHash, error) {
+	if revision == "" {
+		revision = "HEAD"
+	}
+
+	if i := strings.LastIndexAny(revision, "~^"); i > 0 {
+		base, err := r.ResolveRevision(revision[:i])
+		if err != nil {
+			return zeroHash, err
+		}
+
+		n := 1
+		if i+1 < len(revision) {
+			if n, err = strconv.Atoi(revision[i+1:]); err != nil {
+				return zeroHash, errors.New("Invalid revision " + revision)
+			}
+		}
+
+		if revision[i] == '^' {
+			if n == 0 {
+				return base, nil
+			}
+			commit, err := r.ReadCommit(base)
+			if err != nil {
+				return zeroHash, err
+			}
+			if n > len(commit.Parents) {
+				return zeroHash, errors.New("Unknown revision " + revision)
+			}
+			return commit.Parents[n-1], nil
+		}
+
+		for ; n > 0; n-- {
+			commit, err := r.ReadCommit(base)
+			if err != nil {
+				return zeroHash, err
+			}
+			if len(commit.Parents) == 0 {
+				return zeroHash, errors.New("Unknown revision " + revision)
+			}
+			base = commit.Parents[0]
+		}
+		return base, nil
+	}
+
+	for _, name := range []string{revision, "refs/" + revision, "refs/heads/" + revision, "refs/tags/" + revision} {
+		if name != "HEAD" && !strings.HasPrefix(name, "refs/") {
+			continue
+		}
+		if id, err := r.readRef(name); err == nil {
+			return id, nil
+		}
+	}
+	if revision == "HEAD" {
+		return zeroHash, errors.New("HEAD does not point to a commit")
+	}
+
+	return r.resolvePrefix(revision)
+}
+
+func (r *GitRepo) resolvePrefix(prefix string) (gitHash, error) {
+	prefix = strings.ToLower(prefix)
+	if len(prefix) < 4 || len(prefix) > 40 || strings.Trim(prefix, "0123456789abcdef") != "" {
+		return zeroHash, errors.New("Unknown revision " + prefix)
+	}
+	if len(prefix) == 40 {
+		return parseHash(prefix)
+	}
+
+	matches := make(map[gitHash]struct{})
+
+	dir := filepath.Join(r.objectsDir(), prefix[:2])
+	if names, err := ioutil.ReadDir(dir); err == nil {
+		for _, info := range names {
+			if strings.HasPrefix(prefix[:2]+info.Name(), prefix) {
+				if id, err := parseHash(prefix[:2] + info.Name()); err == nil {
+					matches[id] = struct{}{}
+				}
+			}
+		}
+	}
+
+	r.mu.Lock()
+	if !r.packsLoaded {
+		if err := r.loadPacks(); err != nil {
+			r.mu.Unlock()
+			return zeroHash, err
+		}
+	}
+	for _, p := range r.packs {
+		for _, id := range p.matchPrefix(prefix) {
+			matches[id] = struct{}{}
+		}
+	}
+	r.mu.Unlock()
+
+	if len(matches) > 1 {
+		return zeroHash, errors.New("Ambiguous revision " + prefix)
+	}
+	for id := range matches {
+		return id, nil
+	}
+	return zeroHash, errors.New("Unknown revision " + prefix)
+}
+
+// Lookup returns the tree entry at the slash separated path p.
+func (r *GitRepo) Lookup(tree gitHash, p string) (gitTreeEntry, error) {
+	entry := gitTreeEntry{Mode: gitModeTree, ID: tree}
+
+	for _, name := range strings.Split(strings.Trim(p, "/"), "/") {
+		if name == "" {
+			continue
+		}
+		if !entry.IsTree() {
+			return gitTreeEntry{}, errPathNotFound
+		}
+
+		entries, err := r.ReadTree(entry.ID)
+		if err != nil {
+			return gitTreeEntry{}, err
+		}
+
+		found := false
+		for _, e := range entries {
+			if e.Name == name {
+				entry = e
+				found = true
+				break
+			}
+		}
+		if !found {
+			return gitTreeEntry{}, errPathNotFound
+		}
+	}
+	return entry, nil
+}
+
+// WalkTree calls fn for every non-tree entry below dir, with its path
+// relative to the root of the tree.
+func (r *GitRepo) WalkTree(tree gitHash, dir string, fn func(p string, entry gitTreeEntry) error) error {
+	entry, err := r.Lookup(tree, dir)
+	if err == errPathNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !entry.IsTree() {
+		return nil
+	}
+	return r.walkTree(entry.ID, strings.Trim(dir, "/"), fn)
+}
+
+func (r *GitRepo) walkTree(tree gitHash, dir string, fn func(p string, entry gitTreeEntry) error) error {
+	entries, err := r.ReadTree(tree)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		p := path.Join(dir, entry.Name)
+		if entry.IsTree() {
+			err = r.walkTree(entry.ID, p, fn)
+		} else {
+			err = fn(p, entry)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DiffTrees calls fn for every non-tree entry below dir differing between the
+// trees a and b, with nil standing for a missing entry.
+func (r *GitRepo) DiffTrees(a gitHash, b gitHash, dir string, fn func(p string, old *gitTreeEntry, new *gitTreeEntry) error) error {
+	subtree := func(tree gitHash) (gitHash, error) {
+		if tree.IsZero() {
+			return zeroHash, nil
+		}
+		entry, err := r.Lookup(tree, dir)
+		if err == errPathNotFound || (err == nil && !entry.IsTree()) {
+			return zeroHash, nil
+		}
+		return entry.ID, err
+	}
+
+	oldTree, err := subtree(a)
+	if err != nil {
+		return err
+	}
+	newTree, err := subtree(b)
+	if err != nil {
+		return err
+	}
+	return r.diffTrees(oldTree, newTree, strings.Trim(dir, "/"), fn)
+}
+
+func (r *GitRepo) diffTrees(a gitHash, b gitHash, dir string, fn func(p string, old *gitTreeEntry, new *gitTreeEntry) error) error {
+	if a == b {
+		return nil
+	}
+
+	entries := func(tree gitHash) (map[string]gitTreeEntry, error) {
+		byName := make(map[string]gitTreeEntry)
+		if tree.IsZero() {
+			return byName, nil
+		}
+		list, err := r.ReadTree(tree)
+		for _, entry := range list {
+			byName[entry.Name] = entry
+		}
+		return byName, err
+	}
+
+	oldEntries, err := entries(a)
+	if err != nil {
+		return err
+	}
+	newEntries, err := entries(b)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(oldEntries)+len(newEntries))
+	for name := range oldEntries {
+		names = append(names, name)
+	}
+	for name := range newEntries {
+		if _, ok := oldEntries[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		o, hasOld := oldEntries[name]
+		n, hasNew := newEntries[name]
+		if hasOld && hasNew && o == n {
+			continue
+		}
+
+		// An entry can turn from a tree into a file or the other way around
+		var oldTree, newTree gitHash
+		var oldFile, newFile *gitTreeEntry
+		if hasOld {
+			if o.IsTree() {
+				oldTree = o.ID
+			} else {
+				oldFile = &o
+			}
+		}
+		if hasNew {
+			if n.IsTree() {
+				newTree = n.ID
+			} else {
+				newFile = &n
+			}
+		}
+
+		p := path.Join(dir, name)
+		if err := r.diffTrees(oldTree, newTree, p, fn); err != nil {
+			return err
+		}
+		if oldFile != nil || newFile != nil {
+			if err := fn(p, oldFile, newFile); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// UpdateTree returns tree with the entry at path p replaced by entry, or
+// removed if entry is nil.
+func (r *GitRepo) UpdateTree(tree gitHash, p string, entry *gitTreeEntry) (gitHash, error) {
+	names := strings.Split(strings.Trim(p, "/"), "/")
+	return r.updateTree(tree, names, entry)
+}
+
+func (r *GitRepo) updateTree(tree gitHash, names []string, entry *gitTreeEntry) (gitHash, error) {
+	entries := make([]gitTreeEntry, 0)
+	if !tree.IsZero() {
+		var err error
+		if entries, err = r.ReadTree(tree); err != nil {
+			return zeroHash, err
+		}
+	}
+
+	index := -1
+	for i, e := range entries {
+		if e.Name == names[0] {
+			index = i
+			break
+		}
+	}
+
+	var replacement *gitTreeEntry
+	if len(names) == 1 {
+		if entry != nil {
+			replacement = &gitTreeEntry{Mode: entry.Mode, Name: names[0], ID: entry.ID}
+		}
+	} else {
+		subtree := zeroHash
+		if index >= 0 && entries[index].IsTree() {
+			subtree = entries[index].ID
+		}
+
+		id, err := r.updateTree(subtree, names[1:], entry)
+		if err != nil {
+			return zeroHash, err
+		}
+		if !id.IsZero() {
+			replacement = &gitTreeEntry{Mode: gitModeTree, Name: names[0], ID: id}
+		}
+	}
+
+	if index >= 0 {
+		entries = append(entries[:index], entries[index+1:]...)
+	}
+	if replacement != nil {
+		entries = append(entries, *replacement)
+	}
+
+	if len(entries) == 0 {
+		return zeroHash, nil
+	}
+	return r.WriteObject("tree", encodeTree(entries))
+}
+
+// EmptyTree writes the empty tree object.
+func (r *GitRepo) EmptyTree() (gitHash, error) {
+	return r.WriteObject("tree", nil)
+}
+
+// Commit records tree as a new commit on the current branch, authored by
+// author or by the committer if author is nil.
+func (r *GitRepo) Commit(tree gitHash, message string, author *gitSignature) (gitHash, error) {
+	ref, err := r.headRef()
+	if err != nil {
+		return zeroHash, err
+	}
+
+	var parents []gitHash
+	parent, err := r.readRef(ref)
+	if err == nil {
+		parents = append(parents, parent)
+	} else {
+		parent = zeroHash
+	}
+
+	sig := r.Signature()
+	authorSig := sig
+	if author != nil {
+		authorSig = *author
+		authorSig.When = sig.When
+	}
+
+	commit := &gitCommit{
+		Tree:      tree,
+		Parents:   parents,
+		Author:    authorSig,
+		Committer: sig,
+		Message:   cleanCommitMessage(message),
+	}
+
+	id, err := r.WriteObject("commit", commit.encode())
+	if err != nil {
+		return zeroHash, err
+	}
+
+	if err := r.UpdateRef(ref, parent, id); err != nil {
+		return zeroHash, err
+	}
+	return id, nil
+}
+
+// Signature returns the identity commits are made with, read from the git
+// configuration.
+func (r *GitRepo) Signature() gitSignature {
+	sig := gitSignature{
+		Name:  "Wiki",
+		Email: "wiki@localhost",
+		When:  time.Now(),
+	}
+
+	filenames := []string{filepath.Join(r.gitDir(), "config")}
+	if home := os.Getenv("HOME"); home != "" {
+		filenames = append(filenames, filepath.Join(home, ".gitconfig"))
+	}
+
+	var name, email string
+	for _, filename := range filenames {
+		values := readGitConfig(filename)
+		if name == "" {
+			name = values["user.name"]
+		}
+		if email == "" {
+			email = values["user.email"]
+		}
+	}
+
+	if name != "" {
+		sig.Name = name
+	}
+	if email != "" {
+		sig.Email = email
+	}
+	return sig
+}
+
+// readGitConfig returns the section.key values of a git configuration file.
+// Subsections and includes are not supported.
+func readGitConfig(filename string) map[string]string {
+	values := make(map[string]string)
+
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return values
+	}
+
+	section := ""
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || line[0] == '#' || line[0] == ';':
+		case line[0] == '[':
+			section = strings.ToLower(strings.Trim(line, "[]"))
+		default:
+			parts := strings.SplitN(line, "=", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			key := section + "." + strings.ToLower(strings.TrimSpace(parts[0]))
+			values[key] = strings.Trim(strings.TrimSpace(parts[1]), "\"")
+		}
+	}
+	return values
+}
+
+// gitIndexEntry is an entry of the index. Stat holds the ctime, mtime, dev,
+// ino, mode, uid, gid and size fields git records for the file.
+type gitIndexEntry struct {
+	Path string
+	ID   gitHash
+	Stat [10]uint32
+}
+
+// unchanged reports whether the stat data of the file proves it unchanged
+// since the index was written at modTime.
+func (e gitIndexEntry) unchanged(info os.FileInfo, modTime time.Time) bool {
+	mtime := info.ModTime()
+	return e.Stat[2] == uint32(mtime.Unix()) &&
+		e.Stat[3] == uint32(mtime.Nanosecond()) &&
+		e.Stat[9] == uint32(info.Size()) &&
+		mtime.Before(modTime)
+}
+
+// setStat records the stat data of the file in the entry.
+func (e *gitIndexEntry) setStat(info os.FileInfo) {
+	mtime := info.ModTime()
+	e.Stat[2], e.Stat[3] = uint32(mtime.Unix()), uint32(mtime.Nanosecond())
+	e.Stat[9] = uint32(info.Size())
+}
+
+// readIndex returns the entries of the index, and the time it was written.
+func (r *GitRepo) readIndex() ([]gitIndexEntry, time.Time, error) {
+	filename := filepath.Join(r.gitDir(), "index")
+	info, err := os.Stat(filename)
+	if os.IsNotExist(err) {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	invalid := errors.New("Invalid index: " + filename)
+	if len(content) < 12+sha1.Size || string(content[:4]) != "DIRC" {
+		return nil, time.Time{}, invalid
+	}
+	data, checksum := content[:len(content)-sha1.Size], content[len(content)-sha1.Size:]
+	if sum := sha1.Sum(data); !bytes.Equal(sum[:], checksum) {
+		return nil, time.Time{}, invalid
+	}
+
+	version := binary.BigEndian.Uint32(data[4:])
+	if version != 2 && version != 3 {
+		return nil, info.ModTime(), nil
+	}
+
+	count := binary.BigEndian.Uint32(data[8:])
+	entries := make([]gitIndexEntry, 0, count)
+	pos := 12
+	for n := uint32(0); n < count; n++ {
+		if pos+62 > len(data) {
+			return nil, time.Time{}, invalid
+		}
+
+		var e gitIndexEntry
+		for i := range e.Stat {
+			e.Stat[i] = binary.BigEndian.Uint32(data[pos+4*i:])
+		}
+		copy(e.ID[:], data[pos+40:pos+60])
+		flags := binary.BigEndian.Uint16(data[pos+60:])
+
+		start := pos + 62
+		if flags&0x4000 != 0 {
+			// Extended flags, in version 3 only
+			start += 2
+		}
+		end := bytes.IndexByte(data[start:], 0)
+		if end < 0 {
+			return nil, time.Time{}, invalid
+		}
+		e.Path = string(data[start : start+end])
+
+		// Entries are padded with 1 to 8 NUL bytes to a multiple of 8
+		pos += (start + end - pos + 8) &^ 7
+		if flags&0x3000 == 0 {
+			entries = append(entries, e)
+		}
+	}
+	return entries, info.ModTime(), nil
+}
+
+// writeIndex replaces the index with entries, sorted by path, waiting up to
+// timeout for the lock.
+func (r *GitRepo) writeIndex(entries []gitIndexEntry, timeout time.Duration) error {
+	var buf bytes.Buffer
+	buf.WriteString("DIRC")
+	binary.Write(&buf, binary.BigEndian, uint32(2))
+	binary.Write(&buf, binary.BigEndian, uint32(len(entries)))
+
+	for _, e := range entries {
+		binary.Write(&buf, binary.BigEndian, e.Stat)
+		buf.Write(e.ID[:])
+
+		flags := len(e.Path)
+		if flags > 0xfff {
+			flags = 0xfff
+		}
+		binary.Write(&buf, binary.BigEndian, uint16(flags))
+		buf.WriteString(e.Path)
+
+		length := 62 + len(e.Path)
+		padding := 8 - length%8
+		buf.Write(make([]byte, padding))
+	}
+
+	checksum := sha1.Sum(buf.Bytes())
+	buf.Write(checksum[:])
+
+	filename := filepath.Join(r.gitDir(), "index")
+	deadline := time.Now().Add(timeout)
+	f, err := os.OpenFile(filename+".lock", os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0664)
+	for os.IsExist(err) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		f, err = os.OpenFile(filename+".lock", os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0664)
+	}
+	if os.IsExist(err) {
+		return errIndexLocked
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		os.Remove(filename + ".lock")
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(filename + ".lock")
+		return err
+	}
+	return os.Rename(filename+".lock", filename)
 }
 
-func (r *GitRepo) Exec(stdin io.Reader, args ...string) ([]byte, error) {
-	args = append([]string{"--git-dir=" + r.Path + "/.git", "--work-tree=" + r.Path}, args...)
-	return ExecGit(stdin, args...)
+// WriteIndex replaces the index with the content of tree.
+func (r *GitRepo) WriteIndex(tree gitHash) error {
+	previous, _, err := r.readIndex()
+	if err != nil {
+		// Rewritten from scratch below
+		previous = nil
+	}
+	stats := make(map[string]gitIndexEntry, len(previous))
+	for _, e := range previous {
+		stats[e.Path] = e
+	}
+
+	entries := make([]gitIndexEntry, 0)
+	err = r.WalkTree(tree, "", func(p string, entry gitTreeEntry) error {
+		mode, err := strconv.ParseUint(entry.Mode, 8, 32)
+		if err != nil {
+			return err
+		}
+
+		e := gitIndexEntry{Path: p, ID: entry.ID}
+		if old, ok := stats[p]; ok && old.ID == entry.ID && old.Stat[6] == uint32(mode) {
+			e.Stat = old.Stat
+		}
+		e.Stat[6] = uint32(mode)
+		entries = append(entries, e)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Path < entries[j].Path
+	})
+
+	return r.writeIndex(entries, gitIndexLockTimeout)
 }
 
+// IsClean reports whether the work tree matches the tree of HEAD.
 func (r *GitRepo) IsClean() (bool, error) {
-	out, err := r.Exec(nil, "status", "--porcelain")
+	changes, err := r.WorkTreeChanges()
 	if err != nil {
 		return false, err
 	}
+	return len(changes) == 0, nil
+}
+
+func (r *GitRepo) ignored(rel string) bool {
+	for _, dir := range r.Ignore {
+		if rel == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// WorkTreeChanges returns the paths of the work tree whose content differs
+// from HEAD, including untracked and deleted files.
+func (r *GitRepo) WorkTreeChanges() ([]string, error) {
+	tracked := make(map[string]gitHash)
+
+	if head, err := r.ResolveRevision("HEAD"); err == nil {
+		commit, err := r.ReadCommit(head)
+		if err != nil {
+			return nil, err
+		}
+		err = r.WalkTree(commit.Tree, "", func(p string, entry gitTreeEntry) error {
+			tracked[p] = entry.ID
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	entries, indexTime, err := r.readIndex()
+	if err != nil {
+		// Every file gets hashed, and the index is left alone
+		log.Println(err)
+		entries = nil
+	}
+	index := make(map[string]*gitIndexEntry, len(entries))
+	for n := range entries {
+		index[entries[n].Path] = &entries[n]
+	}
+	refreshed := false
+
+	// Files modified this recently could change again without their mtime
+	// showing it, so their stat data is not recorded
+	settled := time.Now().Add(-time.Second)
+
+	changes := make([]string, 0)
+	err = filepath.Walk(r.Path, func(filename string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(r.Path, filename)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if info.IsDir() {
+			if rel == ".git" || r.ignored(rel) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		id, ok := tracked[rel]
+		delete(tracked, rel)
+		if !ok {
+			changes = append(changes, rel)
+			return nil
+		}
+
+		entry := index[rel]
+		if entry != nil && entry.ID == id && entry.unchanged(info, indexTime) {
+			return nil
+		}
+
+		var content []byte
+		if info.Mode()&os.ModeSymlink != 0 {
+			target, err := os.Readlink(filename)
+			if err != nil {
+				return err
+			}
+			content = []byte(target)
+		} else {
+			if content, err = ioutil.ReadFile(filename); err != nil {
+				return err
+			}
+		}
+
+		if id != hashObject("blob", content) {
+			changes = append(changes, rel)
+		} else if entry != nil && entry.ID == id && info.ModTime().Before(settled) {
+			entry.setStat(info)
+			refreshed = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for p := range tracked {
+		changes = append(changes, p)
+	}
+	sort.Strings(changes)
+
+	if refreshed {
+		// Only an optimization: skipped while someone else writes the index
+		if err := r.writeIndex(entries, 0); err != nil && err != errIndexLocked {
+			log.Println(err)
+		}
+	}
+	return changes, nil
+}
+
+// readWorkTreeFile returns the content of the file at the slash separated
+// path p of the work tree, and the mode git records it with.
+func (r *GitRepo) readWorkTreeFile(p string) ([]byte, string, error) {
+	filename := filepath.Join(r.Path, filepath.FromSlash(p))
+	info, err := os.Lstat(filename)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(filename)
+		return []byte(target), gitModeSymlink, err
+	}
+
+	mode := gitModeFile
+	if info.Mode()&0111 != 0 {
+		mode = gitModeExec
+	}
+	content, err := ioutil.ReadFile(filename)
+	return content, mode, err
+}
+
+// WorkTreeTree returns tree updated with the content of paths in the work tree.
+func (r *GitRepo) WorkTreeTree(tree gitHash, paths []string) (gitHash, error) {
+	for _, p := range paths {
+		content, mode, err := r.readWorkTreeFile(p)
+		if os.IsNotExist(err) {
+			if tree, err = r.UpdateTree(tree, p, nil); err != nil {
+				return zeroHash, err
+			}
+			continue
+		}
+		if err != nil {
+			return zeroHash, err
+		}
+
+		blob, err := r.WriteObject("blob", content)
+		if err != nil {
+			return zeroHash, err
+		}
+		if tree, err = r.UpdateTree(tree, p, &gitTreeEntry{Mode: mode, ID: blob}); err != nil {
+			return zeroHash, err
+		}
+	}
+	return tree, nil
+}
+
+// Checkout restores the paths of the work tree to their content in tree,
+// removing those it does not hold along with the directories left empty.
+func (r *GitRepo) Checkout(tree gitHash, paths []string) error {
+	for _, p := range paths {
+		filename := filepath.Join(r.Path, filepath.FromSlash(p))
+		if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		entry, err := r.Lookup(tree, p)
+		if err == errPathNotFound {
+			for dir := filepath.Dir(filename); dir != r.Path && strings.HasPrefix(dir, r.Path); dir = filepath.Dir(dir) {
+				if os.Remove(dir) != nil {
+					break
+				}
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		content, err := r.ReadBlob(entry.ID)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(filename), 0770); err != nil {
+			return err
+		}
 
-	return len(out) == 0, nil
+		switch entry.Mode {
+		case gitModeSymlink:
+			err = os.Symlink(string(content), filename)
+		case gitModeExec:
+			err = ioutil.WriteFile(filename, content, 0770)
+		default:
+			err = ioutil.WriteFile(filename, content, 0660)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func ExecGit(stdin io.Reader, args ...string) ([]byte, error) {
-	cmd := exec.Command("git", args...)
-	cmd.Stdin = stdin
-	return cmd.CombinedOutput()
+const stashRef = "refs/stash"
+
+// Stash saves tree, holding the state of the work tree, as git stash does.
+func (r *GitRepo) Stash(tree gitHash, message string) (gitHash, error) {
+	ref, err := r.headRef()
+	if err != nil {
+		return zeroHash, err
+	}
+	head, err := r.readRef(ref)
+	if err != nil {
+		return zeroHash, err
+	}
+	commit, err := r.ReadCommit(head)
+	if err != nil {
+		return zeroHash, err
+	}
+
+	subject := strings.SplitN(commit.Message, "\n", 2)[0]
+	branch := strings.TrimPrefix(ref, "refs/heads/")
+	on := branch + ": " + head.String()[:7] + " " + subject
+	if message == "" {
+		message = "WIP on " + on
+	} else {
+		message = "On " + branch + ": " + strings.SplitN(message, "\n", 2)[0]
+	}
+
+	// The index is kept matching HEAD
+	sig := r.Signature()
+	index := &gitCommit{
+		Tree:      commit.Tree,
+		Parents:   []gitHash{head},
+		Author:    sig,
+		Committer: sig,
+		Message:   cleanCommitMessage("index on " + on),
+	}
+	indexID, err := r.WriteObject("commit", index.encode())
+	if err != nil {
+		return zeroHash, err
+	}
+
+	stash := &gitCommit{
+		Tree:      tree,
+		Parents:   []gitHash{head, indexID},
+		Author:    sig,
+		Committer: sig,
+		Message:   cleanCommitMessage(message),
+	}
+	id, err := r.WriteObject("commit", stash.encode())
+	if err != nil {
+		return zeroHash, err
+	}
+
+	old, err := r.readRef(stashRef)
+	if err != nil {
+		old = zeroHash
+	}
+	if err := r.UpdateRef(stashRef, old, id); err != nil {
+		return zeroHash, err
+	}
+
+	filename := filepath.Join(r.gitDir(), "logs", filepath.FromSlash(stashRef))
+	if err := os.MkdirAll(filepath.Dir(filename), 0775); err != nil {
+		return zeroHash, err
+	}
+	reflog, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0664)
+	if err != nil {
+		return zeroHash, err
+	}
+	defer reflog.Close()
+	_, err = reflog.WriteString(old.String() + " " + id.String() + " " + sig.String() + "\t" + message + "\n")
+	return id, err
+}
+
+var errStopLog = errors.New("Stop log")
+
+// Log calls fn for every commit reachable from id, most recent first. fn
+// can return errStopLog to end the walk early.
+func (r *GitRepo) Log(id gitHash, fn func(commit *gitCommit) error) error {
+	seen := map[gitHash]struct{}{id: struct{}{}}
+
+	first, err := r.ReadCommit(id)
+	if err != nil {
+		return err
+	}
+	queue := []*gitCommit{first}
+
+	for len(queue) > 0 {
+		// Pick the most recent pending commit, like git log does
+		next := 0
+		for i, c := range queue {
+			if c.Committer.When.After(queue[next].Committer.When) {
+				next = i
+			}
+		}
+		commit := queue[next]
+		queue = append(queue[:next], queue[next+1:]...)
+
+		if err := fn(commit); err != nil {
+			if err == errStopLog {
+				return nil
+			}
+			return err
+		}
+
+		for _, parent := range commit.Parents {
+			if _, ok := seen[parent]; ok {
+				continue
+			}
+			seen[parent] = struct{}{}
+
+			c, err := r.ReadCommit(parent)
+			if err != nil {
+				return err
+			}
+			queue = append(queue, c)
+		}
+	}
+	return nil
 }
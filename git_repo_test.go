@@ -0,0 +1,621 @@
+package main
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/sha1"
+	"encoding/binary"
+	"hash/crc32"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testPackObject is an object of a pack written by writeTestPack: a whole
+// object, an offset delta against the object at index Base of the pack, or a
+// reference delta against the object BaseID.
+type testPackObject struct {
+	Type   int
+	Data   []byte // content, or delta
+	Base   int
+	BaseID gitHash
+	ID     gitHash // of the object once the delta is applied
+}
+
+// testDeltaSize encodes a size of a delta header.
+func testDeltaSize(size int) []byte {
+	var buf []byte
+	for {
+		c := byte(size & 0x7f)
+		size >>= 7
+		if size == 0 {
+			return append(buf, c)
+		}
+		buf = append(buf, c|0x80)
+	}
+}
+
+// testDelta returns the delta from a base of baseSize bytes to a result of
+// resultSize bytes made of ops.
+func testDelta(baseSize int, resultSize int, ops ...[]byte) []byte {
+	delta := append(testDeltaSize(baseSize), testDeltaSize(resultSize)...)
+	for _, op := range ops {
+		delta = append(delta, op...)
+	}
+	return delta
+}
+
+// testCopy returns the delta instruction copying size bytes of the base from
+// offset, leaving out the zero bytes as git does.
+func testCopy(offset int, size int) []byte {
+	op := []byte{0x80}
+	for i := uint(0); i < 4; i++ {
+		if b := byte(offset >> (8 * i)); b != 0 {
+			op[0] |= 1 << i
+			op = append(op, b)
+		}
+	}
+	if size != 0x10000 {
+		for i := uint(0); i < 3; i++ {
+			if b := byte(size >> (8 * i)); b != 0 {
+				op[0] |= 0x10 << i
+				op = append(op, b)
+			}
+		}
+	}
+	return op
+}
+
+func testInsert(data string) []byte {
+	return append([]byte{byte(len(data))}, data...)
+}
+
+func testCompress(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// writeTestPack writes objects as a pack with its version 2 index to the
+// repository. With large, every offset goes through the table of 64-bit
+// offsets of the index.
+func writeTestPack(t *testing.T, r *GitRepo, objects []testPackObject, large bool) {
+	var pack bytes.Buffer
+	pack.WriteString("PACK")
+	binary.Write(&pack, binary.BigEndian, uint32(2))
+	binary.Write(&pack, binary.BigEndian, uint32(len(objects)))
+
+	offsets := make([]int64, len(objects))
+	crcs := make([]uint32, len(objects))
+	for i, object := range objects {
+		offsets[i] = int64(pack.Len())
+
+		var raw []byte
+		size := len(object.Data)
+		c := byte(object.Type<<4) | byte(size&0x0f)
+		for size >>= 4; size != 0; size >>= 7 {
+			raw = append(raw, c|0x80)
+			c = byte(size & 0x7f)
+		}
+		raw = append(raw, c)
+
+		switch object.Type {
+		case packObjOfsDelta:
+			distance := offsets[i] - offsets[object.Base]
+			encoded := []byte{byte(distance & 0x7f)}
+			for distance >>= 7; distance != 0; distance >>= 7 {
+				distance--
+				encoded = append([]byte{0x80 | byte(distance&0x7f)}, encoded...)
+			}
+			raw = append(raw, encoded...)
+		case packObjRefDelta:
+			raw = append(raw, object.BaseID[:]...)
+		}
+
+		raw = append(raw, testCompress(t, object.Data)...)
+		crcs[i] = crc32.ChecksumIEEE(raw)
+		pack.Write(raw)
+	}
+	packSum := sha1.Sum(pack.Bytes())
+	pack.Write(packSum[:])
+
+	order := make([]int, len(objects))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return bytes.Compare(objects[order[i]].ID[:], objects[order[j]].ID[:]) < 0
+	})
+
+	var idx bytes.Buffer
+	idx.Write([]byte{0xff, 't', 'O', 'c'})
+	binary.Write(&idx, binary.BigEndian, uint32(2))
+	for b := 0; b < 256; b++ {
+		count := 0
+		for _, i := range order {
+			if int(objects[i].ID[0]) <= b {
+				count++
+			}
+		}
+		binary.Write(&idx, binary.BigEndian, uint32(count))
+	}
+	for _, i := range order {
+		idx.Write(objects[i].ID[:])
+	}
+	for _, i := range order {
+		binary.Write(&idx, binary.BigEndian, crcs[i])
+	}
+	for n, i := range order {
+		if large {
+			binary.Write(&idx, binary.BigEndian, uint32(0x80000000|n))
+		} else {
+			binary.Write(&idx, binary.BigEndian, uint32(offsets[i]))
+		}
+	}
+	if large {
+		for _, i := range order {
+			binary.Write(&idx, binary.BigEndian, uint64(offsets[i]))
+		}
+	}
+	idx.Write(packSum[:])
+	idxSum := sha1.Sum(idx.Bytes())
+	idx.Write(idxSum[:])
+
+	name := filepath.Join(r.objectsDir(), "pack", "pack-"+gitHash(packSum).String())
+	if err := ioutil.WriteFile(name+".pack", pack.Bytes(), 0664); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(name+".idx", idx.Bytes(), 0664); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTestRepo(t *testing.T) *GitRepo {
+	r := &GitRepo{Path: t.TempDir(), Ignore: []string{stateDir}}
+	if err := r.Init(); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func checkObject(t *testing.T, r *GitRepo, id gitHash, wantType string, want []byte) {
+	t.Helper()
+	objType, data, err := r.ReadObject(id)
+	if err != nil {
+		t.Fatalf("ReadObject(%s): %v", id, err)
+	}
+	if objType != wantType || !bytes.Equal(data, want) {
+		t.Errorf("ReadObject(%s) = %s %q, want %s %q", id, objType, data, wantType, want)
+	}
+}
+
+func TestApplyDelta(t *testing.T) {
+	long := bytes.Repeat([]byte("0123456789abcdef"), 0x1000+1)
+
+	tests := []struct {
+		name  string
+		base  []byte
+		delta []byte
+		want  string
+		err   bool
+	}{
+		{
+			name:  "copy and insert",
+			base:  []byte("hello world"),
+			delta: testDelta(11, 11, testCopy(0, 6), testInsert("there")),
+			want:  "hello there",
+		},
+		{
+			name:  "copy from an offset",
+			base:  []byte("hello world"),
+			delta: testDelta(11, 7, testInsert("big "), testCopy(6, 3)),
+			want:  "big wor",
+		},
+		{
+			name:  "copy of 0x10000 bytes has no size",
+			base:  long,
+			delta: testDelta(len(long), 0x10000+2, testCopy(16, 0x10000), testInsert("!!")),
+			want:  string(long[16:16+0x10000]) + "!!",
+		},
+		{
+			name:  "base size mismatch",
+			base:  []byte("hello"),
+			delta: testDelta(6, 5, testCopy(0, 5)),
+			err:   true,
+		},
+		{
+			name:  "copy out of the base",
+			base:  []byte("hello"),
+			delta: testDelta(5, 6, testCopy(2, 6)),
+			err:   true,
+		},
+		{
+			name:  "result size mismatch",
+			base:  []byte("hello"),
+			delta: testDelta(5, 6, testCopy(0, 5)),
+			err:   true,
+		},
+		{
+			name:  "reserved opcode",
+			base:  []byte("hello"),
+			delta: testDelta(5, 5, []byte{0}),
+			err:   true,
+		},
+		{
+			name:  "truncated insert",
+			base:  []byte("hello"),
+			delta: testDelta(5, 5, []byte{5, 'a', 'b'}),
+			err:   true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := applyDelta(test.base, test.delta)
+		if test.err {
+			if err == nil {
+				t.Errorf("%s: got %q, want an error", test.name, got)
+			}
+			continue
+		}
+		if err != nil || string(got) != test.want {
+			t.Errorf("%s: got %q, %v, want %q", test.name, got, err, test.want)
+		}
+	}
+}
+
+func TestPackDeltaChain(t *testing.T) {
+	v1 := []byte("first line\nsecond line\n")
+	v2 := []byte("first line\nsecond line\nthird line\n")
+	v3 := []byte("zeroth line\nfirst line\nsecond line\nthird line\n")
+	v4 := []byte("zeroth line\nfirst line\n")
+	tree := encodeTree([]gitTreeEntry{{Mode: gitModeFile, Name: "page.md", ID: hashObject("blob", v4)}})
+
+	// An incompressible object puts the base of the first delta more than
+	// 16384 bytes away, which takes three bytes to encode
+	noise := make([]byte, 40000)
+	rand.New(rand.NewSource(1)).Read(noise)
+
+	objects := []testPackObject{
+		{Type: packObjBlob, Data: v1, ID: hashObject("blob", v1)},
+		{Type: packObjTree, Data: tree, ID: hashObject("tree", tree)},
+		{Type: packObjBlob, Data: noise, ID: hashObject("blob", noise)},
+		{
+			Type: packObjOfsDelta,
+			Data: testDelta(len(v1), len(v2), testCopy(0, len(v1)), testInsert("third line\n")),
+			Base: 0,
+			ID:   hashObject("blob", v2),
+		},
+		{
+			Type: packObjOfsDelta,
+			Data: testDelta(len(v2), len(v3), testInsert("zeroth line\n"), testCopy(0, len(v2))),
+			Base: 3,
+			ID:   hashObject("blob", v3),
+		},
+		{
+			Type:   packObjRefDelta,
+			Data:   testDelta(len(v3), len(v4), testCopy(0, len("zeroth line\nfirst line\n"))),
+			BaseID: hashObject("blob", v3),
+			ID:     hashObject("blob", v4),
+		},
+	}
+
+	for _, large := range []bool{false, true} {
+		r := newTestRepo(t)
+		writeTestPack(t, r, objects, large)
+
+		checkObject(t, r, objects[0].ID, "blob", v1)
+		checkObject(t, r, objects[1].ID, "tree", tree)
+		checkObject(t, r, objects[2].ID, "blob", noise)
+		checkObject(t, r, objects[3].ID, "blob", v2)
+		checkObject(t, r, objects[4].ID, "blob", v3)
+		checkObject(t, r, objects[5].ID, "blob", v4)
+
+		id, err := r.ResolveRevision(objects[4].ID.String()[:8])
+		if err != nil || id != objects[4].ID {
+			t.Errorf("ResolveRevision(prefix of %s) = %s, %v", objects[4].ID, id, err)
+		}
+		if _, _, err := r.ReadObject(hashObject("blob", []byte("missing"))); err == nil {
+			t.Error("ReadObject of a missing object succeeded")
+		}
+	}
+}
+
+func TestPackRefDeltaLooseBase(t *testing.T) {
+	r := newTestRepo(t)
+
+	base := []byte("a page\nwritten loose\n")
+	baseID, err := r.WriteObject("blob", base)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := []byte("a page\npacked later\n")
+	writeTestPack(t, r, []testPackObject{{
+		Type:   packObjRefDelta,
+		Data:   testDelta(len(base), len(result), testCopy(0, 7), testInsert("packed later\n")),
+		BaseID: baseID,
+		ID:     hashObject("blob", result),
+	}}, false)
+
+	checkObject(t, r, hashObject("blob", result), "blob", result)
+}
+
+func TestLooseObjects(t *testing.T) {
+	r := newTestRepo(t)
+
+	blob := []byte("some content\n")
+	blobID, err := r.WriteObject("blob", blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if blobID != hashObject("blob", blob) {
+		t.Errorf("WriteObject returned %s, want %s", blobID, hashObject("blob", blob))
+	}
+	// Writing an existing object is a no-op
+	if id, err := r.WriteObject("blob", blob); err != nil || id != blobID {
+		t.Errorf("WriteObject again = %s, %v", id, err)
+	}
+	checkObject(t, r, blobID, "blob", blob)
+
+	tree, err := r.UpdateTree(zeroHash, "pages/page.md", &gitTreeEntry{Mode: gitModeFile, ID: blobID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, err := r.Lookup(tree, "pages/page.md")
+	if err != nil || entry.ID != blobID {
+		t.Errorf("Lookup = %v, %v, want %s", entry, err, blobID)
+	}
+
+	commit, err := r.Commit(tree, "Add page", &gitSignature{Name: "alice", Email: "alice@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := r.ResolveRevision("HEAD")
+	if err != nil || head != commit {
+		t.Fatalf("HEAD = %s, %v, want %s", head, err, commit)
+	}
+	c, err := r.ReadCommit(commit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Tree != tree || len(c.Parents) != 0 || c.Author.Name != "alice" || c.Message != "Add page\n" {
+		t.Errorf("ReadCommit = %+v", c)
+	}
+}
+
+// testTree returns a tree holding the files, by slash separated path.
+func testTree(t *testing.T, r *GitRepo, files map[string]gitTreeEntry) gitHash {
+	tree := zeroHash
+	for p, entry := range files {
+		var err error
+		if tree, err = r.UpdateTree(tree, p, &entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return tree
+}
+
+func TestIndexRoundTrip(t *testing.T) {
+	r := newTestRepo(t)
+
+	blob := func(content string) gitHash {
+		id, err := r.WriteObject("blob", []byte(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+
+	// Paths of several lengths, so that entries take from 1 to 8 bytes of
+	// padding
+	files := map[string]gitTreeEntry{
+		"ab":                {Mode: gitModeFile, ID: blob("ab")},
+		"pages/b.md":        {Mode: gitModeFile, ID: blob("b")},
+		"pages/sub/c.md":    {Mode: gitModeFile, ID: blob("c")},
+		"pages/longer-d.md": {Mode: gitModeExec, ID: blob("d")},
+		"link":              {Mode: gitModeSymlink, ID: blob("pages/b.md")},
+	}
+	tree := testTree(t, r, files)
+
+	if err := r.WriteIndex(tree); err != nil {
+		t.Fatal(err)
+	}
+	entries, _, err := r.readIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(files) {
+		t.Fatalf("readIndex returned %d entries, want %d", len(entries), len(files))
+	}
+	for i, e := range entries {
+		if i > 0 && entries[i-1].Path >= e.Path {
+			t.Errorf("entries out of order: %s before %s", entries[i-1].Path, e.Path)
+		}
+		want, ok := files[e.Path]
+		if !ok {
+			t.Errorf("unexpected entry %s", e.Path)
+			continue
+		}
+		if e.ID != want.ID || strconv.FormatUint(uint64(e.Stat[6]), 8) != want.Mode {
+			t.Errorf("entry %s = %s %o, want %s %s", e.Path, e.ID, e.Stat[6], want.ID, want.Mode)
+		}
+	}
+
+	// Stat data is kept for the entries which don't change
+	for i := range entries {
+		entries[i].Stat[2], entries[i].Stat[9] = 1234, 5678
+	}
+	if err := r.writeIndex(entries, 0); err != nil {
+		t.Fatal(err)
+	}
+	files["pages/b.md"] = gitTreeEntry{Mode: gitModeFile, ID: blob("b changed")}
+	if err := r.WriteIndex(testTree(t, r, files)); err != nil {
+		t.Fatal(err)
+	}
+	entries, _, err = r.readIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		kept := e.Stat[2] == 1234 && e.Stat[9] == 5678
+		if kept == (e.Path == "pages/b.md") {
+			t.Errorf("entry %s: stat data kept = %v", e.Path, kept)
+		}
+	}
+}
+
+func TestWriteIndexLocked(t *testing.T) {
+	r := newTestRepo(t)
+	id, err := r.WriteObject("blob", []byte("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree := testTree(t, r, map[string]gitTreeEntry{"a.md": {Mode: gitModeFile, ID: id}})
+	lock := filepath.Join(r.gitDir(), "index.lock")
+	if err := ioutil.WriteFile(lock, []byte("held by git"), 0664); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.writeIndex(nil, 0); err != errIndexLocked {
+		t.Errorf("writeIndex with the lock held = %v, want %v", err, errIndexLocked)
+	}
+	if content, err := ioutil.ReadFile(lock); err != nil || string(content) != "held by git" {
+		t.Errorf("lock = %q, %v; want it untouched", content, err)
+	}
+
+	// The lock is waited for
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		os.Remove(lock)
+	}()
+	if err := r.WriteIndex(tree); err != nil {
+		t.Fatal(err)
+	}
+	entries, _, err := r.readIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Path != "a.md" {
+		t.Errorf("readIndex = %+v", entries)
+	}
+}
+
+func TestReadIndexVersion3(t *testing.T) {
+	r := newTestRepo(t)
+
+	id := hashObject("blob", []byte("x"))
+	var buf bytes.Buffer
+	buf.WriteString("DIRC")
+	binary.Write(&buf, binary.BigEndian, uint32(3))
+	binary.Write(&buf, binary.BigEndian, uint32(2))
+	for _, p := range []string{"intent-to-add.md", "plain.md"} {
+		var stat [10]uint32
+		stat[6] = 0100644
+		binary.Write(&buf, binary.BigEndian, stat)
+		buf.Write(id[:])
+		length := 62 + len(p)
+		if p == "intent-to-add.md" {
+			binary.Write(&buf, binary.BigEndian, uint16(0x4000|len(p)))
+			binary.Write(&buf, binary.BigEndian, uint16(0x2000))
+			length += 2
+		} else {
+			binary.Write(&buf, binary.BigEndian, uint16(len(p)))
+		}
+		buf.WriteString(p)
+		buf.Write(make([]byte, 8-length%8))
+	}
+	sum := sha1.Sum(buf.Bytes())
+	buf.Write(sum[:])
+	if err := ioutil.WriteFile(filepath.Join(r.gitDir(), "index"), buf.Bytes(), 0664); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, _, err := r.readIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Path != "intent-to-add.md" || entries[1].Path != "plain.md" || entries[1].ID != id {
+		t.Errorf("readIndex = %+v", entries)
+	}
+
+	// A corrupt index is reported rather than misread
+	content := buf.Bytes()
+	content[20] ^= 0xff
+	if err := ioutil.WriteFile(filepath.Join(r.gitDir(), "index"), content, 0664); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := r.readIndex(); err == nil {
+		t.Error("readIndex of a corrupt index succeeded")
+	}
+}
+
+// TestGitCompatibility checks the repository against the git binary, when
+// there is one: what is written must pass git fsck and leave git status
+// clean, and what git packs must read back the same.
+func TestGitCompatibility(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	s := NewGitStorage(t.TempDir(), pagesDir, pageExtension, attachmentsDir, stateDir)
+	if err := s.Init(); err != nil {
+		t.Fatal(err)
+	}
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", s.repo.Path}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_CONFIG_NOSYSTEM=1", "HOME="+t.TempDir())
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+		return string(out)
+	}
+
+	var body string
+	bodies := make(map[string]string)
+	for i := 0; i < 30; i++ {
+		body += strings.Repeat("line of text ", i%7+1) + "\n"
+		title := "page" + string(rune('a'+i%5))
+		revision, _, err := s.SetPageBody(title, body, "Update "+title, "", Author{Name: "alice", Email: "alice@example.com"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		bodies[revision+" "+title] = body
+	}
+	if _, err := s.RenamePage("pagea", "dir/pagea", "Rename", true, false, Author{}); err != nil {
+		t.Fatal(err)
+	}
+
+	git("fsck", "--strict", "--no-dangling")
+	if status := git("status", "--porcelain"); status != "" {
+		t.Errorf("git status after native writes:\n%s", status)
+	}
+
+	// Repacking turns the objects into delta chains
+	git("gc", "--aggressive", "--prune=now")
+	reopened := NewGitStorage(s.repo.Path, pagesDir, pageExtension, attachmentsDir, stateDir)
+	for key, want := range bodies {
+		parts := strings.SplitN(key, " ", 2)
+		got, err := reopened.PageBody(parts[1], parts[0])
+		if err != nil || string(got) != want {
+			t.Errorf("PageBody(%s, %s) after gc = %q, %v, want %q", parts[1], parts[0], got, err, want)
+		}
+	}
+	if clean, err := reopened.repo.IsClean(); err != nil || !clean {
+		t.Errorf("IsClean after gc = %v, %v", clean, err)
+	}
+}
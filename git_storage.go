@@ -5,207 +5,747 @@ import (
 	"errors"
 	"io/ioutil"
 	"os"
-	"os/exec"
 	"path"
 	"path/filepath"
 	"sort"
 	"strings"
 	"sync"
-	"syscall"
 )
 
+// GitStorage stores pages in a git repository.
 type GitStorage struct {
-	mu            sync.Mutex
-	pagesDir      string
-	pageExtension string
-	repo          *GitRepo
+	mu             sync.RWMutex
+	pagesDir       string
+	pageExtension  string
+	attachmentsDir string
+	repo           *GitRepo
+
+	// CommitExternalEdits commits the changes made to the work tree outside
+	// of the wiki before each write, instead of refusing it.
+	CommitExternalEdits bool
 }
 
+// externalEditMessage is the message of the commits of the changes made to
+// the work tree outside of the wiki.
+const externalEditMessage = "External edit"
+
+// DirtyWorkTree is returned by the writes while the work tree holds changes
+// made outside of the wiki.
 type DirtyWorkTree struct {
 }
 
 func (d *DirtyWorkTree) Error() string {
-	return "Work tree is not clean"
+	return "Work tree is not clean: an administrator must commit, stash or discard the changes made to the data directory at /_/worktree"
+}
+
+// WorkTreeChange is a file of the work tree changed outside of the wiki. Diff
+// is nil for binary files.
+type WorkTreeChange struct {
+	Path string
+	Kind string
+	Diff *PageDiff
 }
 
-func NewGitStorage(path string, pagesDir string, pageExtension string) *GitStorage {
+// NewGitStorage returns a storage for the repository at path. The ignored
+// top level directories of the work tree are left out of the repository.
+func NewGitStorage(path string, pagesDir string, pageExtension string, attachmentsDir string, ignore ...string) *GitStorage {
 	return &GitStorage{
-		pagesDir:      pagesDir,
-		pageExtension: pageExtension,
+		pagesDir:       pagesDir,
+		pageExtension:  pageExtension,
+		attachmentsDir: attachmentsDir,
 		repo: &GitRepo{
-			Path: path,
+			Path:   path,
+			Ignore: ignore,
 		},
 	}
 }
 
+// ensureIsClean fails unless the work tree matches HEAD, or commits the
+// changes if CommitExternalEdits is set. The caller must hold the write lock.
 func (s *GitStorage) ensureIsClean() error {
 	if clean, err := s.repo.IsClean(); !clean {
 		if err != nil {
 			return err
 		}
+		if s.CommitExternalEdits {
+			return s.commitWorkTree(externalEditMessage, Author{})
+		}
 		return &DirtyWorkTree{}
 	}
 	return nil
 }
 
-func (s *GitStorage) DeletePage(title string) error {
+// WorkTreeChanges returns the changes made to the work tree outside of the
+// wiki.
+func (s *GitStorage) WorkTreeChanges() ([]WorkTreeChange, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	paths, err := s.repo.WorkTreeChanges()
+	if err != nil {
+		return nil, err
+	}
+	head, err := s.readCommit("HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]WorkTreeChange, 0, len(paths))
+	for _, p := range paths {
+		change := WorkTreeChange{Path: p, Kind: changeModify}
+
+		var old []byte
+		entry, err := s.repo.Lookup(head.Tree, p)
+		if err == errPathNotFound {
+			change.Kind = changeAdd
+		} else if err != nil {
+			return nil, err
+		} else if old, err = s.repo.ReadBlob(entry.ID); err != nil {
+			return nil, err
+		}
+
+		content, _, err := s.repo.readWorkTreeFile(p)
+		if os.IsNotExist(err) {
+			change.Kind = changeDelete
+		} else if err != nil {
+			return nil, err
+		}
+
+		if !bytes.Contains(old, []byte{0}) && !bytes.Contains(content, []byte{0}) {
+			change.Diff = newPageDiff("a/"+p, "b/"+p, string(old), string(content))
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// CommitWorkTree commits the changes made to the work tree outside of the
+// wiki.
+func (s *GitStorage) CommitWorkTree(message string, author Author) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if err := s.ensureIsClean(); err != nil {
+	return s.commitWorkTree(message, author)
+}
+
+func (s *GitStorage) commitWorkTree(message string, author Author) error {
+	paths, err := s.repo.WorkTreeChanges()
+	if err != nil || len(paths) == 0 {
+		return err
+	}
+	head, err := s.readCommit("HEAD")
+	if err != nil {
 		return err
 	}
 
-	filename := path.Join(s.pagesDir, title+s.pageExtension)
-
-	if out, err := s.repo.Exec(nil, "rm", filename); err != nil {
-		if len(out) > 0 {
-			return errors.New(string(out))
-		}
+	tree, err := s.repo.WorkTreeTree(head.Tree, paths)
+	if err != nil {
 		return err
 	}
+	_, err = s.commit(tree, message, author)
+	return err
+}
 
-	if out, err := s.repo.Exec(nil, "commit", "--allow-empty", "-m", "Delete "+title); err != nil {
-		if len(out) > 0 {
-			return errors.New(string(out))
-		}
+// StashWorkTree saves the changes made to the work tree outside of the wiki
+// as a git stash, then discards them.
+func (s *GitStorage) StashWorkTree(message string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	paths, err := s.repo.WorkTreeChanges()
+	if err != nil || len(paths) == 0 {
+		return err
+	}
+	head, err := s.readCommit("HEAD")
+	if err != nil {
 		return err
 	}
 
-	return nil
+	tree, err := s.repo.WorkTreeTree(head.Tree, paths)
+	if err != nil {
+		return err
+	}
+	if _, err := s.repo.Stash(tree, message); err != nil {
+		return err
+	}
+	return s.repo.Checkout(head.Tree, paths)
 }
 
-func (s *GitStorage) Diff(title string, body string) ([]byte, error) {
+// DiscardWorkTree restores the work tree to HEAD, losing the changes made
+// outside of the wiki.
+func (s *GitStorage) DiscardWorkTree() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if err := s.ensureIsClean(); err != nil {
+
+	paths, err := s.repo.WorkTreeChanges()
+	if err != nil || len(paths) == 0 {
+		return err
+	}
+	head, err := s.readCommit("HEAD")
+	if err != nil {
+		return err
+	}
+	return s.repo.Checkout(head.Tree, paths)
+}
+
+func (s *GitStorage) pagePath(title string) string {
+	return path.Join(s.pagesDir, title+s.pageExtension)
+}
+
+// pageTitle returns the title of the page stored at p, or false if p is not
+// a page.
+func (s *GitStorage) pageTitle(p string) (string, bool) {
+	if !strings.HasPrefix(p, s.pagesDir+"/") || !strings.HasSuffix(p, s.pageExtension) {
+		return "", false
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(p, s.pagesDir+"/"), s.pageExtension), true
+}
+
+func (s *GitStorage) attachmentPath(title string, name string) string {
+	return path.Join(s.attachmentsDir, title, name)
+}
+
+// attachments returns the entries of the files attached to the page title in
+// tree.
+func (s *GitStorage) attachments(tree gitHash, title string) ([]gitTreeEntry, error) {
+	dir, err := s.repo.Lookup(tree, path.Join(s.attachmentsDir, title))
+	if err == errPathNotFound {
+		return nil, nil
+	}
+	if err != nil || !dir.IsTree() {
+		return nil, err
+	}
+
+	entries, err := s.repo.ReadTree(dir.ID)
+	if err != nil {
 		return nil, err
 	}
 
-	file, err := ioutil.TempFile(os.TempDir(), "")
+	files := make([]gitTreeEntry, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsTree() {
+			files = append(files, entry)
+		}
+	}
+	return files, nil
+}
+
+func sameEntries(a []gitTreeEntry, b []gitTreeEntry) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *GitStorage) readCommit(revision string) (*gitCommit, error) {
+	id, err := s.repo.ResolveRevision(revision)
 	if err != nil {
 		return nil, err
 	}
-	defer os.Remove(file.Name())
+	return s.repo.ReadCommit(id)
+}
 
-	err = ioutil.WriteFile(file.Name(), []byte(body), 0600)
+// snapshot returns the commit HEAD points to, without waiting for a write
+// in progress to finish.
+func (s *GitStorage) snapshot() (*gitCommit, error) {
+	s.mu.RLock()
+	id, err := s.repo.ResolveRevision("HEAD")
+	s.mu.RUnlock()
 	if err != nil {
 		return nil, err
 	}
+	return s.repo.ReadCommit(id)
+}
+
+// snapshotAt returns the commit of revision, or the current snapshot if
+// revision designates HEAD.
+func (s *GitStorage) snapshotAt(revision string) (*gitCommit, error) {
+	if revision == "" || revision == "HEAD" {
+		return s.snapshot()
+	}
+	return s.readCommit(revision)
+}
 
-	out, err := ExecGit(strings.NewReader(body), "diff", "--no-index", "--",
-		path.Join(s.repo.Path, s.pagesDir, title+s.pageExtension), file.Name())
+// commit records tree as a new commit and updates the index to match.
+func (s *GitStorage) commit(tree gitHash, message string, author Author) (gitHash, error) {
+	var sig *gitSignature
+	if author.Name != "" {
+		// Angle brackets and new lines would corrupt the commit header
+		clean := strings.NewReplacer("<", "", ">", "", "\n", " ")
+		sig = &gitSignature{Name: clean.Replace(author.Name), Email: clean.Replace(author.Email)}
+	}
 
+	id, err := s.repo.Commit(tree, message, sig)
 	if err != nil {
-		if exitError, ok := err.(*exec.ExitError); ok {
-			if exitStatus := exitError.Sys().(syscall.WaitStatus).ExitStatus(); exitStatus == 1 {
-				return out, nil
-			}
+		return gitHash{}, err
+	}
+	return id, s.repo.WriteIndex(tree)
+}
+
+func (s *GitStorage) DeletePage(title string, author Author) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.ensureIsClean(); err != nil {
+		return "", err
+	}
+
+	head, err := s.readCommit("HEAD")
+	if err != nil {
+		return "", err
+	}
+
+	p := s.pagePath(title)
+	if _, err := s.repo.Lookup(head.Tree, p); err != nil {
+		if err == errPathNotFound {
+			return "", &PageNotFound{Title: title}
 		}
+		return "", err
+	}
 
-		if len(out) > 0 {
-			return nil, errors.New(string(out))
+	attachments, err := s.attachments(head.Tree, title)
+	if err != nil {
+		return "", err
+	}
+
+	removed := []string{p}
+	for _, entry := range attachments {
+		removed = append(removed, s.attachmentPath(title, entry.Name))
+	}
+
+	tree := head.Tree
+	for _, p := range removed {
+		if tree, err = s.repo.UpdateTree(tree, p, nil); err != nil {
+			return "", err
+		}
+	}
+	if tree.IsZero() {
+		if tree, err = s.repo.EmptyTree(); err != nil {
+			return "", err
 		}
+	}
 
-		return nil, err
+	id, err := s.commit(tree, "Delete "+title, author)
+	if err != nil {
+		return "", err
 	}
 
-	return out, nil
+	for _, p := range removed {
+		if err := removeFile(s.repo.Path, filepath.FromSlash(p)); err != nil {
+			return "", err
+		}
+	}
+	return id.String(), nil
 }
 
-func (s *GitStorage) History(title string) ([]Commit, error) {
+func (s *GitStorage) DeleteAttachment(title string, name string, message string, author Author) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if err := s.ensureIsClean(); err != nil {
-		return nil, err
+		return err
 	}
 
-	out, err := s.repo.Exec(nil, "log", "--name-status", "--", path.Join(s.pagesDir, title+s.pageExtension))
+	head, err := s.readCommit("HEAD")
+	if err != nil {
+		return err
+	}
 
+	p := s.attachmentPath(title, name)
+	entry, err := s.repo.Lookup(head.Tree, p)
+	if err == errPathNotFound || (err == nil && entry.IsTree()) || !validAttachmentName(name) {
+		return errors.New("Attachment not found: " + name)
+	}
 	if err != nil {
-		if len(out) > 0 {
-			return nil, errors.New(string(out))
+		return err
+	}
+
+	tree, err := s.repo.UpdateTree(head.Tree, p, nil)
+	if err != nil {
+		return err
+	}
+	if tree.IsZero() {
+		if tree, err = s.repo.EmptyTree(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := s.commit(tree, message, author); err != nil {
+		return err
+	}
+
+	return removeFile(s.repo.Path, filepath.FromSlash(p))
+}
+
+// removeFile removes the file at name relative to root along with the
+// directories left empty.
+func removeFile(root string, name string) error {
+	if err := os.Remove(filepath.Join(root, name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	for dir := filepath.Dir(name); dir != "." && dir != string(filepath.Separator); dir = filepath.Dir(dir) {
+		if err := os.Remove(filepath.Join(root, dir)); err != nil {
+			break
 		}
+	}
+	return nil
+}
+
+func (s *GitStorage) Attachment(title string, name string, revision string) ([]byte, error) {
+	commit, err := s.snapshotAt(revision)
+	if err != nil {
 		return nil, err
 	}
 
-	commits := logParser(string(out), title)
-	lines := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	entry, err := s.repo.Lookup(commit.Tree, s.attachmentPath(title, name))
+	if err == errPathNotFound || (err == nil && entry.IsTree()) || !validAttachmentName(name) {
+		return nil, errors.New("Attachment not found: " + name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ReadBlob(entry.ID)
+}
 
-	revisions := make([]string, len(lines))
-	for index, line := range lines {
-		parts := strings.SplitN(line, " ", 2)
-		revisions[index] = parts[0]
+func (s *GitStorage) Attachments(title string, revision string) ([]string, error) {
+	commit, err := s.snapshotAt(revision)
+	if err != nil {
+		return nil, err
 	}
 
-	return commits, nil
+	attachments, err := s.attachments(commit.Tree, title)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(attachments))
+	for _, entry := range attachments {
+		names = append(names, entry.Name)
+	}
+	sort.Strings(names)
+	return names, nil
 }
 
-func (s *GitStorage) Init() error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	out, err := ExecGit(nil, "init", s.repo.Path)
+func (s *GitStorage) Diff(title string, body string) (*PageDiff, error) {
+	head, err := s.snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.pageBody(head, title)
+	if err != nil && err != errPathNotFound {
+		return nil, err
+	}
+
+	p := s.pagePath(title)
+	return newPageDiff("a/"+p, "b/"+p, string(current), body), nil
+}
+
+func (s *GitStorage) DiffRevisions(title string, from string, to string) (*PageDiff, error) {
+	history, err := s.History(title)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.DiffPages(titleAt(history, title, from), from, titleAt(history, title, to), to)
+}
 
+func (s *GitStorage) DiffPages(fromTitle string, from string, toTitle string, to string) (*PageDiff, error) {
+	fromBody, err := s.revisionBody(fromTitle, from)
 	if err != nil {
-		if len(out) > 0 {
-			return errors.New(string(out))
+		return nil, err
+	}
+	toBody, err := s.revisionBody(toTitle, to)
+	if err != nil {
+		return nil, err
+	}
+
+	fromName := "a/" + s.pagePath(fromTitle)
+	if from == "" {
+		fromName = "/dev/null"
+	}
+	return newPageDiff(fromName, "b/"+s.pagePath(toTitle), string(fromBody), string(toBody)), nil
+}
+
+// revisionBody returns the body of the page title at revision, which is empty
+// if the page did not exist then.
+func (s *GitStorage) revisionBody(title string, revision string) ([]byte, error) {
+	if revision == "" {
+		return nil, nil
+	}
+
+	commit, err := s.snapshotAt(revision)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := s.pageBody(commit, title)
+	if err == errPathNotFound {
+		return nil, nil
+	}
+	return body, err
+}
+
+func newCommit(c *gitCommit) Commit {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(c.Message, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
 		}
-		return err
 	}
 
-	out, err = s.repo.Exec(nil, "show-ref", "--verify", "--quiet", "--", "refs/heads/master")
+	return Commit{
+		ID:          c.ID.String(),
+		Date:        c.Author.When.Format(gitDateFormat),
+		Message:     strings.Join(lines, " "),
+		AuthorName:  c.Author.Name,
+		AuthorEmail: c.Author.Email,
+	}
+}
 
-	if err == nil {
+func (s *GitStorage) Head() (string, error) {
+	head, err := s.snapshot()
+	if err != nil {
+		return "", err
+	}
+	return head.ID.String(), nil
+}
+
+func (s *GitStorage) History(title string) ([]Commit, error) {
+	head, err := s.snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make([]Commit, 0)
+
+	err = s.repo.Log(head.ID, func(c *gitCommit) error {
+		p := s.pagePath(title)
+		entry, err := s.repo.Lookup(c.Tree, p)
+		if err != nil && err != errPathNotFound {
+			return err
+		}
+		exists := err == nil
+
+		attachments, err := s.attachments(c.Tree, title)
+		if err != nil {
+			return err
+		}
+
+		// Skip commits leaving the page and its attachments as they were in
+		// one of their parents
+		changed := true
+		var parents []*gitCommit
+		for _, parent := range c.Parents {
+			pc, err := s.repo.ReadCommit(parent)
+			if err != nil {
+				return err
+			}
+			parents = append(parents, pc)
+
+			parentEntry, err := s.repo.Lookup(pc.Tree, p)
+			if err != nil && err != errPathNotFound {
+				return err
+			}
+			parentExists := err == nil
+
+			parentAttachments, err := s.attachments(pc.Tree, title)
+			if err != nil {
+				return err
+			}
+
+			if parentExists == exists && parentEntry.ID == entry.ID && sameEntries(parentAttachments, attachments) {
+				changed = false
+				break
+			}
+		}
+		if len(c.Parents) == 0 && !exists && len(attachments) == 0 {
+			changed = false
+		}
+
+		if !changed {
+			return nil
+		}
+
+		commit := newCommit(c)
+		commit.Title = title
+		commit.Delete = !exists
+
+		// Follow the page to its previous title when it was renamed
+		if exists && len(parents) > 0 {
+			from, err := s.renamedFrom(parents[0], c, p, entry.ID)
+			if err != nil {
+				return err
+			}
+			if from != "" {
+				title, _ = s.pageTitle(from)
+				commit.RenamedFrom = title
+			}
+		}
+
+		commits = append(commits, commit)
 		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return commits, nil
+}
+
+func (s *GitStorage) RecentChanges(filter ChangeFilter) ([]Change, error) {
+	head, err := s.snapshot()
+	if err != nil {
+		return nil, err
 	}
 
-	if exitError, ok := err.(*exec.ExitError); ok {
-		if exitStatus := exitError.Sys().(syscall.WaitStatus).ExitStatus(); exitStatus == 1 {
-			out, err = s.repo.Exec(nil, "commit", "--allow-empty", "-m", "Initial commit")
-			if err == nil {
+	collector := newChangeCollector(filter)
+	err = s.repo.Log(head.ID, func(c *gitCommit) error {
+		match, done := filter.matchCommit(c.Author.When, Author{Name: c.Author.Name, Email: c.Author.Email})
+		if done {
+			return errStopLog
+		}
+		if !match {
+			return nil
+		}
+
+		// Merges are compared with their first parent, like git log does
+		var parent *gitCommit
+		var parentTree gitHash
+		if len(c.Parents) > 0 {
+			pc, err := s.repo.ReadCommit(c.Parents[0])
+			if err != nil {
+				return err
+			}
+			parent, parentTree = pc, pc.Tree
+		}
+
+		changes := make([]Change, 0)
+		err := s.repo.DiffTrees(parentTree, c.Tree, s.pagesDir, func(p string, old *gitTreeEntry, new *gitTreeEntry) error {
+			title, ok := s.pageTitle(p)
+			if !ok {
 				return nil
 			}
-			if len(out) > 0 {
-				return errors.New(string(out))
+
+			change := Change{Commit: newCommit(c), Kind: changeModify}
+			change.Title = title
+			if parent != nil {
+				change.Parent = parent.ID.String()
+			}
+
+			switch {
+			case old == nil:
+				change.Kind = changeAdd
+				if parent != nil {
+					from, err := s.renamedFrom(parent, c, p, new.ID)
+					if err != nil {
+						return err
+					}
+					change.RenamedFrom, _ = s.pageTitle(from)
+				}
+			case new == nil:
+				change.Kind = changeDelete
+				change.Delete = true
 			}
+			changes = append(changes, change)
+			return nil
+		})
+		if err != nil {
 			return err
 		}
+
+		for _, change := range changes {
+			if collector.add(change) {
+				return errStopLog
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	return err
+	return collector.changes, nil
 }
 
-func (s *GitStorage) ListDeletedPages() ([]string, error) {
+// renamedFrom returns the path of the page renamed to p by commit, or an
+// empty string.
+func (s *GitStorage) renamedFrom(parent *gitCommit, commit *gitCommit, p string, id gitHash) (string, error) {
+	if _, err := s.repo.Lookup(parent.Tree, p); err != errPathNotFound {
+		return "", err
+	}
+
+	from := ""
+	err := s.repo.WalkTree(parent.Tree, s.pagesDir, func(q string, entry gitTreeEntry) error {
+		if entry.ID != id {
+			return nil
+		}
+
+		current, err := s.repo.Lookup(commit.Tree, q)
+		if err != nil && err != errPathNotFound {
+			return err
+		}
+		if err == errPathNotFound || current.ID != id {
+			from = q
+			return errStopLog
+		}
+		return nil
+	})
+	if err != nil && err != errStopLog {
+		return "", err
+	}
+	return from, nil
+}
+
+func (s *GitStorage) Init() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if err := s.ensureIsClean(); err != nil {
-		return nil, err
-	}
 
-	var out []byte
-	var err error
+	if err := s.repo.Init(); err != nil {
+		return err
+	}
 
-	if out, err = s.repo.Exec(nil, "log", "-z", "--name-status", "--oneline", "--", s.pagesDir); err != nil {
-		if len(out) > 0 {
-			return nil, errors.New(string(out))
+	if _, err := s.readCommit("HEAD"); err == nil {
+		if s.CommitExternalEdits {
+			return s.ensureIsClean()
 		}
-		return nil, err
+		return nil
 	}
 
-	byteTitles := bytes.Split(bytes.TrimSuffix(out, []byte{0}), []byte{0})
+	tree, err := s.repo.EmptyTree()
+	if err != nil {
+		return err
+	}
+	_, err = s.commit(tree, "Initial commit", Author{})
+	return err
+}
+
+func (s *GitStorage) ListDeletedPages() ([]string, error) {
+	head, err := s.snapshot()
+	if err != nil {
+		return nil, err
+	}
 
 	titles := make(map[string]struct{})
-	for _, title := range byteTitles {
-		title := string(title)
-		if !strings.HasPrefix(title, s.pagesDir+"/") || !strings.HasSuffix(title, s.pageExtension) {
-			continue
+	visited := make(map[gitHash]struct{})
+
+	err = s.repo.Log(head.ID, func(c *gitCommit) error {
+		entry, err := s.repo.Lookup(c.Tree, s.pagesDir)
+		if err == errPathNotFound || !entry.IsTree() {
+			return nil
+		}
+		if err != nil {
+			return err
 		}
-		title = strings.TrimPrefix(title, s.pagesDir+"/")
-		title = strings.TrimSuffix(title, s.pageExtension)
-		titles[title] = struct{}{}
+		return s.collectTitles(entry.ID, s.pagesDir, titles, visited)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	var notDeletedTitles []string
-
-	notDeletedTitles, err = s.listPages()
+	notDeletedTitles, err := s.listPages(head)
 	if err != nil {
 		return nil, err
 	}
@@ -216,7 +756,7 @@ func (s *GitStorage) ListDeletedPages() ([]string, error) {
 
 	_titles := make([]string, 0)
 
-	for title, _ := range titles {
+	for title := range titles {
 		_titles = append(_titles, title)
 	}
 
@@ -225,173 +765,388 @@ func (s *GitStorage) ListDeletedPages() ([]string, error) {
 	return _titles, nil
 }
 
-func (s *GitStorage) ListPages() ([]string, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	if err := s.ensureIsClean(); err != nil {
-		return nil, err
+// collectTitles adds the titles of the pages below tree to titles, skipping
+// the trees already visited.
+func (s *GitStorage) collectTitles(tree gitHash, dir string, titles map[string]struct{}, visited map[gitHash]struct{}) error {
+	if _, ok := visited[tree]; ok {
+		return nil
 	}
-	return s.listPages()
-}
+	visited[tree] = struct{}{}
 
-func (s *GitStorage) listPages() ([]string, error) {
-	out, err := s.repo.Exec(nil, "ls-tree", "-z", "-r", "HEAD", "--name-only", "--", s.pagesDir)
+	entries, err := s.repo.ReadTree(tree)
 	if err != nil {
-		if len(out) > 0 {
-			return nil, errors.New(string(out))
-		}
-		return nil, err
+		return err
 	}
 
-	if len(out) == 0 {
-		return nil, nil
+	for _, entry := range entries {
+		p := path.Join(dir, entry.Name)
+		if entry.IsTree() {
+			if err := s.collectTitles(entry.ID, p, titles, visited); err != nil {
+				return err
+			}
+		} else if title, ok := s.pageTitle(p); ok {
+			titles[title] = struct{}{}
+		}
 	}
+	return nil
+}
 
-	byteTitles := bytes.Split(bytes.TrimSuffix(out, []byte{0}), []byte{0})
+func (s *GitStorage) ListPages() ([]string, error) {
+	head, err := s.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	return s.listPages(head)
+}
 
+func (s *GitStorage) listPages(head *gitCommit) ([]string, error) {
 	titles := make([]string, 0)
-	for _, title := range byteTitles {
-		title := string(title)
-		title = strings.TrimPrefix(title, s.pagesDir+"/")
-		title = strings.TrimSuffix(title, s.pageExtension)
-		titles = append(titles, title)
+	err := s.repo.WalkTree(head.Tree, s.pagesDir, func(p string, entry gitTreeEntry) error {
+		if title, ok := s.pageTitle(p); ok {
+			titles = append(titles, title)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return titles, nil
 }
 
 func (s *GitStorage) PageBody(title string, revision string) ([]byte, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	if err := s.ensureIsClean(); err != nil {
+	commit, err := s.snapshotAt(revision)
+	if err != nil {
 		return nil, err
 	}
 
-	out, err := s.repo.Exec(nil, "cat-file", "-p", revision+":"+path.Join(s.pagesDir, title+s.pageExtension))
+	body, err := s.pageBody(commit, title)
+	if err == errPathNotFound {
+		return nil, &PageNotFound{Title: title}
+	}
+	return body, err
+}
 
+func (s *GitStorage) pageBody(commit *gitCommit, title string) ([]byte, error) {
+	entry, err := s.repo.Lookup(commit.Tree, s.pagePath(title))
 	if err != nil {
-		if len(out) > 0 {
-			return nil, errors.New(string(out))
-		}
 		return nil, err
 	}
-	return out, nil
+	return s.repo.ReadBlob(entry.ID)
+}
+
+// mergeConcurrentEdit merges body with the changes made to the page since
+// baseRevision.
+func (s *GitStorage) mergeConcurrentEdit(head *gitCommit, title string, body string, baseRevision string) (string, error) {
+	baseCommit, err := s.readCommit(baseRevision)
+	if err != nil {
+		return "", err
+	}
+
+	base, err := s.pageBody(baseCommit, title)
+	if err != nil && err != errPathNotFound {
+		return "", err
+	}
+
+	current, err := s.pageBody(head, title)
+	if err != nil && err != errPathNotFound {
+		return "", err
+	}
+
+	if string(current) == string(base) {
+		return body, nil
+	}
+
+	merged, ok := merge3(string(base), body, string(current))
+	if !ok {
+		return "", &EditConflict{
+			Title:    title,
+			Revision: head.ID.String(),
+			Yours:    body,
+			Current:  string(current),
+			Merged:   merged,
+		}
+	}
+	return merged, nil
 }
 
-func (s *GitStorage) Search(q string) ([]PageSearchResult, error) {
+func (s *GitStorage) Parent(revision string) (string, error) {
+	commit, err := s.readCommit(revision)
+	if err != nil {
+		return "", err
+	}
+	if len(commit.Parents) == 0 {
+		return "", nil
+	}
+	return commit.Parents[0].String(), nil
+}
+
+func (s *GitStorage) RenamePage(title string, newTitle string, message string, redirect bool, updateLinks bool, author Author) (string, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if err := s.ensureIsClean(); err != nil {
-		return nil, err
+		return "", err
+	}
+
+	head, err := s.readCommit("HEAD")
+	if err != nil {
+		return "", err
 	}
 
-	out, err := s.repo.Exec(nil, "grep", "--ignore-case", "-e", q, "--", "HEAD", s.pagesDir)
+	from, to := s.pagePath(title), s.pagePath(newTitle)
+	if t, ok := s.pageTitle(to); !ok || t != newTitle || newTitle == title {
+		return "", errors.New("Invalid title: " + newTitle)
+	}
 
+	entry, err := s.repo.Lookup(head.Tree, from)
+	if err == errPathNotFound {
+		return "", &PageNotFound{Title: title}
+	}
 	if err != nil {
-		if msg, ok := err.(*exec.ExitError); ok {
-			if exitCode := msg.Sys().(syscall.WaitStatus).ExitStatus(); exitCode == 1 {
-				// No results
-				return nil, nil
-			}
+		return "", err
+	}
+
+	if _, err := s.repo.Lookup(head.Tree, to); err != errPathNotFound {
+		if err == nil {
+			return "", errors.New("Page already exists: " + newTitle)
 		}
+		return "", err
+	}
+
+	// Work tree files to update once committed, nil meaning removal
+	files := make(map[string][]byte)
+
+	body, err := s.repo.ReadBlob(entry.ID)
+	if err != nil {
+		return "", err
+	}
+	tree, err := s.repo.UpdateTree(head.Tree, to, &entry)
+	if err != nil {
+		return "", err
+	}
+	files[to] = body
 
-		if len(out) > 0 {
-			return nil, errors.New(string(out))
+	attachments, err := s.attachments(head.Tree, title)
+	if err != nil {
+		return "", err
+	}
+	for _, e := range attachments {
+		content, err := s.repo.ReadBlob(e.ID)
+		if err != nil {
+			return "", err
 		}
-		return nil, err
+
+		attachmentFrom, attachmentTo := s.attachmentPath(title, e.Name), s.attachmentPath(newTitle, e.Name)
+		if tree, err = s.repo.UpdateTree(tree, attachmentTo, &e); err != nil {
+			return "", err
+		}
+		if tree, err = s.repo.UpdateTree(tree, attachmentFrom, nil); err != nil {
+			return "", err
+		}
+		files[attachmentFrom] = nil
+		files[attachmentTo] = content
 	}
 
-	results := make(map[string][]string)
+	if redirect {
+		stub := []byte(redirectStub(newTitle))
+		blob, err := s.repo.WriteObject("blob", stub)
+		if err != nil {
+			return "", err
+		}
+		if tree, err = s.repo.UpdateTree(tree, from, &gitTreeEntry{Mode: gitModeFile, ID: blob}); err != nil {
+			return "", err
+		}
+		files[from] = stub
+	} else {
+		if tree, err = s.repo.UpdateTree(tree, from, nil); err != nil {
+			return "", err
+		}
+		files[from] = nil
+	}
+
+	if updateLinks {
+		err := s.repo.WalkTree(head.Tree, s.pagesDir, func(p string, e gitTreeEntry) error {
+			if p == from {
+				return nil
+			}
+			if _, ok := s.pageTitle(p); !ok {
+				return nil
+			}
+
+			body, err := s.repo.ReadBlob(e.ID)
+			if err != nil {
+				return err
+			}
+			updated := rewriteLinks(string(body), title, newTitle)
+			if updated == string(body) {
+				return nil
+			}
+
+			blob, err := s.repo.WriteObject("blob", []byte(updated))
+			if err != nil {
+				return err
+			}
+			e.ID = blob
+			if tree, err = s.repo.UpdateTree(tree, p, &e); err != nil {
+				return err
+			}
+			files[p] = []byte(updated)
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	id, err := s.commit(tree, message, author)
+	if err != nil {
+		return "", err
+	}
+
+	for p, content := range files {
+		if content == nil {
+			err = removeFile(s.repo.Path, filepath.FromSlash(p))
+		} else {
+			err = s.writeFile(p, content)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return id.String(), nil
+}
 
-	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
-		tokens := strings.SplitN(line, ":", 2)
-		pageName := strings.TrimPrefix(strings.TrimRight(tokens[0], pageExtension), pagesDir+"/")
+func (s *GitStorage) Search(q string, options SearchOptions) ([]PageSearchResult, error) {
+	re, err := options.pattern(q)
+	if err != nil {
+		return nil, err
+	}
 
-		results[pageName] = append(results[pageName], tokens[1])
+	head, err := s.snapshot()
+	if err != nil {
+		return nil, err
 	}
 
 	searchResults := make([]PageSearchResult, 0)
 
-	for pageName, lines := range results {
-		pageSearchResult := PageSearchResult{
-			Title: pageName,
-			Lines: lines,
+	err = s.repo.WalkTree(head.Tree, s.pagesDir, func(p string, entry gitTreeEntry) error {
+		title, ok := s.pageTitle(p)
+		if !ok || !strings.HasPrefix(title, options.Prefix) {
+			return nil
 		}
-		searchResults = append(searchResults, pageSearchResult)
+
+		body, err := s.repo.ReadBlob(entry.ID)
+		if err != nil {
+			return err
+		}
+
+		if result, ok := matchPattern(re, title, string(body)); ok {
+			searchResults = append(searchResults, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return searchResults, nil
 }
 
-func (s *GitStorage) SetPageBody(title string, body string, message string) error {
+func (s *GitStorage) SetAttachment(title string, name string, content []byte, message string, author Author) error {
+	if !validAttachmentName(name) {
+		return errors.New("Invalid attachment name: " + name)
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if err := s.ensureIsClean(); err != nil {
 		return err
 	}
 
-	filename := path.Join(s.repo.Path, s.pagesDir, title+s.pageExtension)
-	dirName := filepath.Dir(filename)
-
-	if err := os.MkdirAll(dirName, 0770); err != nil {
+	head, err := s.readCommit("HEAD")
+	if err != nil {
 		return err
 	}
 
-	if err := ioutil.WriteFile(filename, []byte(body), 0660); err != nil {
+	if _, err := s.repo.Lookup(head.Tree, s.pagePath(title)); err != nil {
+		if err == errPathNotFound {
+			return &PageNotFound{Title: title}
+		}
 		return err
 	}
 
-	out, err := s.repo.Exec(nil, "add", filename)
+	p := s.attachmentPath(title, name)
+	if entry, err := s.repo.Lookup(head.Tree, p); err == nil && entry.IsTree() {
+		return errors.New("Invalid attachment name: " + name)
+	}
+
+	blob, err := s.repo.WriteObject("blob", content)
 	if err != nil {
-		if len(out) > 0 {
-			return errors.New(string(out))
-		}
 		return err
 	}
-
-	out, err = s.repo.Exec(strings.NewReader(message), "commit", "--allow-empty", "-F", "-")
+	tree, err := s.repo.UpdateTree(head.Tree, p, &gitTreeEntry{Mode: gitModeFile, ID: blob})
 	if err != nil {
-		if len(out) > 0 {
-			return errors.New(string(out))
-		}
 		return err
 	}
 
-	return nil
+	if _, err := s.commit(tree, message, author); err != nil {
+		return err
+	}
+
+	return s.writeFile(p, content)
 }
 
-func logParser(log string, title string) []Commit {
-	lines := strings.Split(strings.TrimSuffix(log, "\n"), "\n")
-	commits := make([]Commit, 0)
+func (s *GitStorage) SetPageBody(title string, body string, message string, baseRevision string, author Author) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.ensureIsClean(); err != nil {
+		return "", false, err
+	}
 
-	var commit *Commit
-	var message []string = make([]string, 0)
+	head, err := s.readCommit("HEAD")
+	if err != nil {
+		return "", false, err
+	}
 
-	for _, line := range lines {
-		switch {
-		case strings.HasPrefix(line, "commit"):
-			if commit != nil {
-				commit.Message = strings.Join(message, " ")
-				commits = append(commits, *commit)
-			}
-			message = make([]string, 0)
-			commit = &Commit{
-				ID: strings.TrimSpace(strings.TrimPrefix(line, "commit")),
-			}
-		case strings.HasPrefix(line, "Author:"):
-		case strings.HasPrefix(line, "Date:"):
-			commit.Date = strings.TrimSpace(strings.TrimPrefix(line, "Date:"))
-		case strings.HasPrefix(line, "    "):
-			message = append(message, strings.TrimPrefix(line, "    "))
-		case strings.HasPrefix(line, "A"):
-		case strings.HasPrefix(line, "M"):
-		case strings.HasPrefix(line, "D"):
-			commit.Delete = true
+	if baseRevision != "" {
+		if body, err = s.mergeConcurrentEdit(head, title, body, baseRevision); err != nil {
+			return "", false, err
 		}
 	}
 
-	if commit != nil {
-		commit.Message = strings.Join(message, " ")
-		commits = append(commits, *commit)
+	blob, err := s.repo.WriteObject("blob", []byte(body))
+	if err != nil {
+		return "", false, err
+	}
+
+	p := s.pagePath(title)
+	if t, ok := s.pageTitle(p); !ok || t != title {
+		return "", false, errors.New("Invalid title: " + title)
+	}
+
+	_, err = s.repo.Lookup(head.Tree, p)
+	if err != nil && err != errPathNotFound {
+		return "", false, err
+	}
+	created := err == errPathNotFound
+
+	tree, err := s.repo.UpdateTree(head.Tree, p, &gitTreeEntry{Mode: gitModeFile, ID: blob})
+	if err != nil {
+		return "", false, err
+	}
+
+	id, err := s.commit(tree, message, author)
+	if err != nil {
+		return "", false, err
+	}
+
+	return id.String(), created, s.writeFile(p, []byte(body))
+}
+
+// writeFile updates the file at the slash separated path p of the work tree.
+func (s *GitStorage) writeFile(p string, content []byte) error {
+	filename := filepath.Join(s.repo.Path, filepath.FromSlash(p))
+	if err := os.MkdirAll(filepath.Dir(filename), 0770); err != nil {
+		return err
 	}
-	return commits
+	return ioutil.WriteFile(filename, content, 0660)
 }
@@ -1,30 +1,78 @@
 package main
 
 import (
+	"errors"
 	"html/template"
+	"io/ioutil"
 	"log"
+	"mime"
 	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
-	"github.com/russross/blackfriday"
+	"github.com/microcosm-cc/bluemonday"
 )
 
+type ACLContext struct {
+	PageContext
+	Source string
+	Saved  bool
+	Error  string
+}
+
 type AllPagesContext struct {
 	PageContext
 	Titles []string
 	Error  string
 }
 
+type AttachmentsContext struct {
+	PageContext
+	Names []string
+	Error string
+}
+
 type AppContext struct {
-	Storage   *GitStorage
+	Storage   PageStore
+	Links     *LinkIndex
+	Search    *SearchIndex
+	Auth      *Authenticator
+	ACL       *ACL
+	Webhooks  *Webhooks
+	WorkTree  *GitStorage // nil unless pages are stored in git
+	sanitizer *bluemonday.Policy
 	templates map[string]*template.Template
 }
 
+type BacklinksContext struct {
+	PageContext
+	Target string
+	Titles []string
+	Error  string
+}
+
 type Commit struct {
-	ID      string
-	Date    string
-	Message string
-	Delete  bool
+	ID          string
+	Date        string
+	Message     string
+	AuthorName  string
+	AuthorEmail string
+	Delete      bool
+	Title       string
+	RenamedFrom string
+}
+
+type CompareContext struct {
+	PageContext
+	From  string
+	To    string
+	View  string
+	Diff  template.HTML
+	Error string
 }
 
 type DeletedContext struct {
@@ -38,6 +86,9 @@ type EditContext struct {
 	Body          template.HTML
 	BodySource    string
 	CommitMessage string
+	BaseRevision  string
+	Conflict      *EditConflict
+	DiffView      string
 	Edit          bool
 	Preview       bool
 	Diff          bool
@@ -49,6 +100,13 @@ type HistoryContext struct {
 	Error   string
 }
 
+type LoginContext struct {
+	PageContext
+	Name  string
+	Next  string
+	Error string
+}
+
 type Page struct {
 	Title string
 	Body  template.HTML
@@ -57,11 +115,28 @@ type Page struct {
 type PageContext struct {
 	Title    string
 	SubTitle string
+	User     *User
+	Login    bool
+	Admin    bool
+	CSRF     string
 }
 
 type PageSearchResult struct {
-	Title string
-	Lines []string
+	Title      string
+	Lines      []string
+	Score      float64
+	Snippet    template.HTML
+	TitleMatch bool
+}
+
+// HistorySearchResult is the latest revision of a page matching a search in
+// the history.
+type HistorySearchResult struct {
+	Title    string
+	Revision string
+	Date     string
+	Deleted  bool
+	Snippet  template.HTML
 }
 
 type PrintableContext struct {
@@ -69,18 +144,96 @@ type PrintableContext struct {
 	Body  template.HTML
 }
 
-type SearchContext struct {
+type RecentChangesContext struct {
+	PageContext
+	Changes      []Change
+	Since        string
+	Until        string
+	Author       string
+	Page         int
+	PreviousPage int
+	NextPage     int
+	More         bool
+	Error        string
+}
+
+type RenameContext struct {
 	PageContext
-	SearchResults []PageSearchResult
-	Query         string
+	NewTitle      string
+	CommitMessage string
+	Redirect      bool
+	UpdateLinks   bool
+	Backlinks     []string
 	Error         string
 }
 
+type SearchContext struct {
+	PageContext
+	SearchResults  []PageSearchResult
+	HistoryResults []HistorySearchResult
+	Query          string
+	Options        SearchOptions
+	History        bool
+	GoTo           string
+	Error          string
+}
+
 type ViewContext struct {
 	PageContext
-	Body     template.HTML
-	Revision string
-	RawBody  string
+	Body           template.HTML
+	Revision       string
+	RawBody        string
+	RedirectedFrom string
+	RedirectTo     string
+	Attachments    []string
+}
+
+type WebhooksContext struct {
+	PageContext
+	Source     string
+	Saved      bool
+	Deliveries []WebhookDelivery
+	Error      string
+}
+
+type WorkTreeContext struct {
+	PageContext
+	Changes []WorkTreeChange
+	Diffs   []template.HTML
+	Message string
+	Done    string
+	Error   string
+}
+
+func (app AppContext) aclHandler(w http.ResponseWriter, r *http.Request) {
+	if !app.authorize(w, r, "", rightAdmin) {
+		return
+	}
+
+	ctx := ACLContext{
+		PageContext: app.pageContext(r, "Access Control", ""),
+	}
+
+	if r.Method == "POST" {
+		ctx.Source = r.FormValue("source")
+		if err := app.ACL.SetSource(ctx.Source); err != nil {
+			ctx.Error = err.Error()
+			w.WriteHeader(http.StatusBadRequest)
+			renderTemplate(app.templates["acl"], w, ctx)
+			return
+		}
+		ctx.Saved = true
+	}
+
+	source, err := app.ACL.Source()
+	if err != nil {
+		ctx.Error = err.Error()
+		renderError(app.templates["acl"], w, ctx, http.StatusInternalServerError)
+		return
+	}
+	ctx.Source = source
+
+	renderTemplate(app.templates["acl"], w, ctx)
 }
 
 func (app AppContext) allPagesHandler(w http.ResponseWriter, r *http.Request) {
@@ -88,10 +241,8 @@ func (app AppContext) allPagesHandler(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		ctx := AllPagesContext{
-			PageContext: PageContext{
-				Title: "All Pages",
-			},
-			Error: err.Error(),
+			PageContext: app.pageContext(r, "All Pages", ""),
+			Error:       err.Error(),
 		}
 		w.WriteHeader(http.StatusInternalServerError)
 		app.templates["allPages"].Execute(w, ctx)
@@ -99,10 +250,8 @@ func (app AppContext) allPagesHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := AllPagesContext{
-		PageContext: PageContext{
-			Title: "All Pages",
-		},
-		Titles: titles,
+		PageContext: app.pageContext(r, "All Pages", ""),
+		Titles:      app.ACL.Readable(currentUser(r), titles),
 	}
 	if err := app.templates["allPages"].Execute(w, ctx); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -110,11 +259,134 @@ func (app AppContext) allPagesHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (app AppContext) attachmentHandler(w http.ResponseWriter, r *http.Request) {
+	title := normalizePath(mux.Vars(r)["title"])
+	name := r.URL.Query().Get("name")
+	if !app.authorize(w, r, title, rightRead) {
+		return
+	}
+
+	content, err := app.Storage.Attachment(title, name, r.URL.Query().Get("revision"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = http.DetectContentType(content)
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	// Uploaded HTML or SVG must not run scripts in the wiki's origin
+	w.Header().Set("Content-Security-Policy", "sandbox")
+	w.Write(content)
+}
+
+func (app AppContext) attachmentsHandler(w http.ResponseWriter, r *http.Request) {
+	title := normalizePath(mux.Vars(r)["title"])
+	if !app.authorize(w, r, title, rightRead) {
+		return
+	}
+
+	ctx := AttachmentsContext{
+		PageContext: app.pageContext(r, title, "attachments"),
+	}
+
+	names, err := app.Storage.Attachments(title, "")
+	if err != nil {
+		ctx.Error = err.Error()
+		renderError(app.templates["attachments"], w, ctx, http.StatusInternalServerError)
+		return
+	}
+
+	ctx.Names = names
+	renderTemplate(app.templates["attachments"], w, ctx)
+}
+
+func (app AppContext) backlinksHandler(w http.ResponseWriter, r *http.Request) {
+	target := normalizePath(r.URL.Query().Get("title"))
+	if !app.authorize(w, r, target, rightRead) {
+		return
+	}
+
+	ctx := BacklinksContext{
+		PageContext: app.pageContext(r, "What links here", ""),
+		Target:      target,
+	}
+
+	titles, err := app.Links.Backlinks(target)
+	if err != nil {
+		ctx.Error = err.Error()
+		renderError(app.templates["backlinks"], w, ctx, http.StatusInternalServerError)
+		return
+	}
+
+	ctx.Titles = app.ACL.Readable(currentUser(r), titles)
+	renderTemplate(app.templates["backlinks"], w, ctx)
+}
+
+func (app AppContext) compareHandler(w http.ResponseWriter, r *http.Request) {
+	title := normalizePath(mux.Vars(r)["title"])
+	if !app.authorize(w, r, title, rightRead) {
+		return
+	}
+
+	query := r.URL.Query()
+	from, to := query.Get("from"), query.Get("to")
+	if to == "" {
+		to = "HEAD"
+	}
+
+	ctx := CompareContext{
+		PageContext: app.pageContext(r, title, "diff"),
+		From:        from,
+		To:          to,
+		View:        diffView(query.Get("view")),
+	}
+
+	// Without a from revision, compare with the previous revision of the page
+	var fromRevision *string
+	if _, ok := query["from"]; ok {
+		fromRevision = &from
+	}
+
+	var diff *PageDiff
+	var err error
+	ctx.From, diff, err = app.diffRevisions(title, fromRevision, to)
+	if err != nil {
+		ctx.Error = err.Error()
+		renderError(app.templates["compare"], w, ctx, http.StatusInternalServerError)
+		return
+	}
+
+	ctx.Diff = renderDiff(diff, ctx.View)
+	renderTemplate(app.templates["compare"], w, ctx)
+}
+
+func (app AppContext) deleteAttachmentHandler(w http.ResponseWriter, r *http.Request) {
+	title := normalizePath(mux.Vars(r)["title"])
+	name := r.FormValue("name")
+	if !app.authorize(w, r, title, rightEdit) {
+		return
+	}
+
+	if err := app.Storage.DeleteAttachment(title, name, "Delete attachment "+name+" of "+title, authorOf(r)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/"+escapeTitle(title)+"?action=attachments", http.StatusSeeOther)
+}
+
 func (app AppContext) deleteHandler(w http.ResponseWriter, r *http.Request) {
 	var title = normalizePath(mux.Vars(r)["title"])
+	if !app.authorize(w, r, title, rightDelete) {
+		return
+	}
 
-	if err := app.Storage.DeletePage(title); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if _, err := app.Storage.DeletePage(title, authorOf(r)); err != nil {
+		http.Error(w, err.Error(), statusOf(err))
 		return
 	}
 
@@ -126,20 +398,16 @@ func (app AppContext) deletedHandler(w http.ResponseWriter, r *http.Request) {
 	var err error
 	if titles, err = app.Storage.ListDeletedPages(); err != nil {
 		ctx := DeletedContext{
-			PageContext: PageContext{
-				Title: "Deleted Pages",
-			},
-			Error: err.Error(),
+			PageContext: app.pageContext(r, "Deleted Pages", ""),
+			Error:       err.Error(),
 		}
 		renderError(app.templates["deleted"], w, ctx, http.StatusInternalServerError)
 		return
 	}
 
 	ctx := DeletedContext{
-		PageContext: PageContext{
-			Title: "Deleted Pages",
-		},
-		Titles: titles,
+		PageContext: app.pageContext(r, "Deleted Pages", ""),
+		Titles:      app.ACL.Readable(currentUser(r), titles),
 	}
 	renderTemplate(app.templates["deleted"], w, ctx)
 }
@@ -148,63 +416,113 @@ func (app AppContext) diffHandler(w http.ResponseWriter, r *http.Request) {
 	body := r.FormValue("body")
 	message := r.FormValue("message")
 	title := normalizePath(mux.Vars(r)["title"])
+	if !app.authorize(w, r, title, rightEdit) {
+		return
+	}
 
-	var out []byte
-	var err error
-	out, err = app.Storage.Diff(title, body)
-
+	diff, err := app.Storage.Diff(title, body)
 	if err != nil {
-		log.Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	view := diffView(r.FormValue("view"))
+
 	ctx := EditContext{
-		PageContext: PageContext{
-			Title:    title,
-			SubTitle: "diff",
-		},
-		Body:          template.HTML(string(out)),
+		PageContext:   app.pageContext(r, title, "diff"),
+		Body:          renderDiff(diff, view),
 		BodySource:    body,
 		CommitMessage: message,
+		BaseRevision:  r.FormValue("base_revision"),
+		DiffView:      view,
 		Diff:          true,
 	}
 	app.templates["diff"].Execute(w, ctx)
 }
 
+// previousRevision returns the revision preceding revision in the history
+// of a page, or an empty string if there is none.
+func previousRevision(history []Commit, revision string) string {
+	for i, commit := range history {
+		if strings.HasPrefix(commit.ID, revision) && i+1 < len(history) {
+			return history[i+1].ID
+		}
+	}
+	return ""
+}
+
+// diffRevisions compares the page title between from, or the revision before
+// to if nil, and to. It returns the revision compared from.
+func (app AppContext) diffRevisions(title string, from *string, to string) (string, *PageDiff, error) {
+	history, err := app.Storage.History(title)
+	if err != nil {
+		return "", nil, err
+	}
+
+	previous := previousRevision(history, to)
+	if from != nil {
+		previous = *from
+	}
+	diff, err := app.Storage.DiffPages(titleAt(history, title, previous), previous, titleAt(history, title, to), to)
+	return previous, diff, err
+}
+
+// diffView returns the diff view to use given the one requested.
+func diffView(view string) string {
+	if view == diffViewSideBySide {
+		return view
+	}
+	return diffViewUnified
+}
+
 func (app AppContext) editHandler(w http.ResponseWriter, r *http.Request) {
 	var title = normalizePath(mux.Vars(r)["title"])
 	var ctx EditContext
+	if !app.authorize(w, r, title, rightEdit) {
+		return
+	}
 
 	if r.Method == "GET" {
-		body, err := app.Storage.PageBody(title, "HEAD")
+		head, err := app.Storage.Head()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		body, err := app.Storage.PageBody(title, head)
 		if err != nil {
 			ctx = EditContext{
-				PageContext: PageContext{
-					Title:    title,
-					SubTitle: "edit",
-				},
-				Edit: true,
+				PageContext:  app.pageContext(r, title, "edit"),
+				BaseRevision: head,
+				Edit:         true,
 			}
 		} else {
 			ctx = EditContext{
-				PageContext: PageContext{
-					Title:    title,
-					SubTitle: "edit",
-				},
-				BodySource: string(body),
-				Edit:       true,
+				PageContext:  app.pageContext(r, title, "edit"),
+				BodySource:   string(body),
+				BaseRevision: head,
+				Edit:         true,
 			}
 		}
 	} else {
 		body := r.FormValue("body")
 		message := r.FormValue("message")
+
+		baseRevision := r.FormValue("base_revision")
+		if baseRevision == "" {
+			head, err := app.Storage.Head()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			baseRevision = head
+		}
+
 		ctx = EditContext{
-			PageContext: PageContext{
-				Title:    title,
-				SubTitle: "edit",
-			},
+			PageContext:   app.pageContext(r, title, "edit"),
 			BodySource:    string(body),
 			CommitMessage: message,
+			BaseRevision:  baseRevision,
 			Edit:          true,
 		}
 	}
@@ -214,31 +532,91 @@ func (app AppContext) editHandler(w http.ResponseWriter, r *http.Request) {
 
 func (app AppContext) historyHandler(w http.ResponseWriter, r *http.Request) {
 	title := normalizePath(mux.Vars(r)["title"])
+	if !app.authorize(w, r, title, rightRead) {
+		return
+	}
 
 	commits, err := app.Storage.History(title)
 
 	if err != nil {
 		ctx := HistoryContext{
-			PageContext: PageContext{
-				Title:    title,
-				SubTitle: "history",
-			},
-			Error: err.Error(),
+			PageContext: app.pageContext(r, title, "history"),
+			Error:       err.Error(),
 		}
 		renderError(app.templates["history"], w, ctx, http.StatusInternalServerError)
 		return
 	}
 
 	ctx := HistoryContext{
-		PageContext: PageContext{
-			Title:    title,
-			SubTitle: "history",
-		},
-		Commits: commits,
+		PageContext: app.pageContext(r, title, "history"),
+		Commits:     commits,
 	}
 	renderTemplate(app.templates["history"], w, ctx)
 }
 
+func (app AppContext) loginHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := LoginContext{
+		PageContext: app.pageContext(r, "Log in", ""),
+		Next:        safeRedirect(r.FormValue("next")),
+	}
+
+	if app.Auth.Mode != authLocal {
+		http.Error(w, "Local accounts are disabled", http.StatusNotFound)
+		return
+	}
+
+	if r.Method == "POST" {
+		ctx.Name = r.FormValue("name")
+
+		user, err := app.Auth.Users.Authenticate(ctx.Name, r.FormValue("password"))
+		if err == nil {
+			err = app.Auth.Login(w, r, user)
+		}
+		if err == nil {
+			http.Redirect(w, r, ctx.Next, http.StatusSeeOther)
+			return
+		}
+
+		ctx.Error = err.Error()
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+
+	renderTemplate(app.templates["login"], w, ctx)
+}
+
+func (app AppContext) logoutHandler(w http.ResponseWriter, r *http.Request) {
+	app.Auth.Logout(w, r)
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// authorize reports whether the user behind r has right on the page title.
+// If not, it replies with an error or sends anonymous users to log in.
+func (app AppContext) authorize(w http.ResponseWriter, r *http.Request, title string, right string) bool {
+	user := currentUser(r)
+	if app.ACL.Allowed(user, title, right) {
+		return true
+	}
+
+	switch {
+	case user == nil && app.Auth.Mode == authLocal && r.Method == "GET":
+		http.Redirect(w, r, "/_/login?next="+url.QueryEscape(r.URL.RequestURI()), http.StatusFound)
+	case user == nil && app.Auth.Mode != authNone:
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+	default:
+		http.Error(w, "Not allowed to "+right+" "+title, http.StatusForbidden)
+	}
+	return false
+}
+
+// authorOf returns the author of the changes made by r.
+func authorOf(r *http.Request) Author {
+	user := currentUser(r)
+	if user == nil {
+		return Author{}
+	}
+	return Author{Name: user.Name, Email: user.Email}
+}
+
 func normalizePath(path string) string {
 	if path == "" {
 		return "home"
@@ -247,44 +625,185 @@ func normalizePath(path string) string {
 	return path
 }
 
+func (app AppContext) pageContext(r *http.Request, title string, subTitle string) PageContext {
+	return PageContext{
+		Title:    title,
+		SubTitle: subTitle,
+		User:     currentUser(r),
+		Login:    app.Auth.Mode == authLocal,
+		Admin:    app.ACL.Allowed(currentUser(r), "", rightAdmin),
+		CSRF:     csrfToken(r),
+	}
+}
+
 func (app AppContext) previewHandler(w http.ResponseWriter, r *http.Request) {
 	body := r.FormValue("body")
 	message := r.FormValue("message")
 	title := normalizePath(mux.Vars(r)["title"])
+	if !app.authorize(w, r, title, rightEdit) {
+		return
+	}
 
 	ctx := EditContext{
-		PageContext: PageContext{
-			Title:    title,
-			SubTitle: "preview",
-		},
-		Body:          template.HTML(renderMarkdown([]byte(body))),
+		PageContext:   app.pageContext(r, title, "preview"),
+		Body:          template.HTML(app.renderMarkdown([]byte(body), title, "")),
 		BodySource:    body,
 		CommitMessage: message,
+		BaseRevision:  r.FormValue("base_revision"),
 		Preview:       true,
 	}
 	app.templates["preview"].Execute(w, ctx)
 }
 
-func renderError(t *template.Template, w http.ResponseWriter, ctx interface{}, s int) {
-	w.WriteHeader(http.StatusInternalServerError)
-	renderTemplate(t, w, ctx)
+func (app AppContext) recentChangesHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	ctx := RecentChangesContext{
+		PageContext: app.pageContext(r, "Recent Changes", ""),
+		Since:       query.Get("since"),
+		Until:       query.Get("until"),
+		Author:      strings.TrimSpace(query.Get("author")),
+		Page:        1,
+	}
+
+	if page, err := strconv.Atoi(query.Get("page")); err == nil && page > 1 {
+		ctx.Page = page
+	}
+	ctx.PreviousPage, ctx.NextPage = ctx.Page-1, ctx.Page+1
+
+	filter := ChangeFilter{
+		Author: ctx.Author,
+		Pages: func(title string) bool {
+			return app.ACL.Allowed(currentUser(r), title, rightRead)
+		},
+		Offset: (ctx.Page - 1) * recentChangesPerPage,
+		// One more to know whether there is a next page
+		Limit: recentChangesPerPage + 1,
+	}
+
+	var err error
+	if ctx.Since != "" {
+		if filter.Since, err = time.ParseInLocation(dateFormat, ctx.Since, time.Local); err != nil {
+			ctx.Error = "Invalid date: " + ctx.Since
+		}
+	}
+	if ctx.Until != "" {
+		// The until date is included
+		if filter.Until, err = time.ParseInLocation(dateFormat, ctx.Until, time.Local); err != nil {
+			ctx.Error = "Invalid date: " + ctx.Until
+		}
+		filter.Until = filter.Until.AddDate(0, 0, 1)
+	}
+	if ctx.Error != "" {
+		w.WriteHeader(http.StatusBadRequest)
+		renderTemplate(app.templates["recentChanges"], w, ctx)
+		return
+	}
+
+	changes, err := app.Storage.RecentChanges(filter)
+	if err != nil {
+		ctx.Error = err.Error()
+		renderError(app.templates["recentChanges"], w, ctx, http.StatusInternalServerError)
+		return
+	}
+
+	if len(changes) > recentChangesPerPage {
+		changes, ctx.More = changes[:recentChangesPerPage], true
+	}
+	ctx.Changes = changes
+	renderTemplate(app.templates["recentChanges"], w, ctx)
+}
+
+// readableResults returns the search results the user behind r can read.
+func (app AppContext) readableResults(r *http.Request, results []PageSearchResult) []PageSearchResult {
+	readable := make([]PageSearchResult, 0, len(results))
+	for _, result := range results {
+		if app.ACL.Allowed(currentUser(r), result.Title, rightRead) {
+			readable = append(readable, result)
+		}
+	}
+	return readable
 }
 
-func renderMarkdown(source []byte) []byte {
-	flags := 0
-	flags |= blackfriday.HTML_TOC
-	flags |= blackfriday.HTML_SAFELINK
+func (app AppContext) renameHandler(w http.ResponseWriter, r *http.Request) {
+	title := normalizePath(mux.Vars(r)["title"])
+	if !app.authorize(w, r, title, rightDelete) {
+		return
+	}
+
+	ctx := RenameContext{
+		PageContext: app.pageContext(r, title, "rename"),
+		NewTitle:    title,
+		Redirect:    true,
+		UpdateLinks: true,
+	}
+
+	if r.Method == "POST" {
+		ctx.NewTitle = strings.Trim(strings.TrimSpace(r.FormValue("new_title")), "/")
+		ctx.CommitMessage = r.FormValue("message")
+		ctx.Redirect = r.FormValue("redirect") != ""
+		ctx.UpdateLinks = r.FormValue("update_links") != ""
 
-	extensions := 0
-	extensions |= blackfriday.EXTENSION_TABLES
-	extensions |= blackfriday.EXTENSION_FENCED_CODE
-	extensions |= blackfriday.EXTENSION_AUTOLINK
-	extensions |= blackfriday.EXTENSION_STRIKETHROUGH
-	extensions |= blackfriday.EXTENSION_HEADER_IDS
-	extensions |= blackfriday.EXTENSION_AUTO_HEADER_IDS
+		message := ctx.CommitMessage
+		if message == "" {
+			message = "Rename " + title + " to " + ctx.NewTitle
+		}
+
+		if !app.authorize(w, r, ctx.NewTitle, rightEdit) {
+			return
+		}
+		// Updating the links edits the pages holding them
+		if ctx.UpdateLinks {
+			backlinks, err := app.Links.Backlinks(title)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			for _, backlink := range backlinks {
+				if !app.authorize(w, r, backlink, rightEdit) {
+					return
+				}
+			}
+		}
+
+		_, err := app.Storage.RenamePage(title, ctx.NewTitle, message, ctx.Redirect, ctx.UpdateLinks, authorOf(r))
+		if err == nil {
+			http.Redirect(w, r, "/"+escapeTitle(ctx.NewTitle), http.StatusSeeOther)
+			return
+		}
+		ctx.Error = err.Error()
+	}
 
-	renderer := blackfriday.HtmlRenderer(flags, "", "")
-	return blackfriday.Markdown(source, renderer, extensions)
+	backlinks, err := app.Links.Backlinks(title)
+	if err != nil {
+		ctx.Error = err.Error()
+	}
+	ctx.Backlinks = app.ACL.Readable(currentUser(r), backlinks)
+
+	if ctx.Error != "" {
+		renderError(app.templates["rename"], w, ctx, http.StatusInternalServerError)
+		return
+	}
+	renderTemplate(app.templates["rename"], w, ctx)
+}
+
+// statusOf returns the HTTP status code reporting err.
+func statusOf(err error) int {
+	switch err.(type) {
+	case *PageNotFound:
+		return http.StatusNotFound
+	case *EditConflict:
+		return http.StatusConflict
+	case *DirtyWorkTree:
+		return http.StatusServiceUnavailable
+	case *QueryError:
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+func renderError(t *template.Template, w http.ResponseWriter, ctx interface{}, s int) {
+	w.WriteHeader(s)
+	renderTemplate(t, w, ctx)
 }
 
 func renderTemplate(t *template.Template, w http.ResponseWriter, ctx interface{}) {
@@ -293,32 +812,88 @@ func renderTemplate(t *template.Template, w http.ResponseWriter, ctx interface{}
 	}
 }
 
+// searchOptions returns the options of the search asked by r.
+func searchOptions(r *http.Request) SearchOptions {
+	query := r.URL.Query()
+	return SearchOptions{
+		Mode:          query.Get("mode"),
+		CaseSensitive: query.Get("case") != "",
+		WholeWord:     query.Get("word") != "",
+		Prefix:        query.Get("prefix"),
+	}
+}
+
 func (app AppContext) searchHandler(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query().Get("q")
+	ctx := SearchContext{
+		PageContext: app.pageContext(r, "Search results for "+q, ""),
+		Query:       q,
+		Options:     searchOptions(r),
+		History:     r.URL.Query().Get("history") != "",
+	}
 
-	searchResults, err := app.Storage.Search(q)
-	if err != nil {
-		ctx := SearchContext{
-			PageContext: PageContext{
-				Title: "Search results for " + q,
-			},
-			Query: q,
-			Error: err.Error(),
+	if ctx.History {
+		allowed := func(title string) bool {
+			return app.ACL.Allowed(currentUser(r), title, rightRead)
+		}
+
+		results, err := app.Search.SearchHistory(q, ctx.Options, allowed, historySearchSize)
+		if err != nil {
+			ctx.Error = err.Error()
+			renderError(app.templates["search"], w, ctx, statusOf(err))
+			return
 		}
-		renderError(app.templates["search"], w, ctx, http.StatusInternalServerError)
+		ctx.HistoryResults = results
+		renderTemplate(app.templates["search"], w, ctx)
 		return
 	}
 
-	ctx := SearchContext{
-		Query:         q,
-		SearchResults: searchResults,
+	searchResults, err := app.Storage.Search(q, ctx.Options)
+	if err != nil {
+		ctx.Error = err.Error()
+		renderError(app.templates["search"], w, ctx, statusOf(err))
+		return
 	}
+	ctx.SearchResults = app.readableResults(r, searchResults)
 
-	app.templates["search"].Execute(w, ctx)
+	// Offer to jump to the page named by the query, whatever its case
+	goTo := strings.TrimSpace(q)
+	for _, result := range ctx.SearchResults {
+		if result.Title == goTo {
+			ctx.GoTo = result.Title
+			break
+		}
+		if ctx.GoTo == "" && strings.EqualFold(result.Title, goTo) {
+			ctx.GoTo = result.Title
+		}
+	}
+
+	renderTemplate(app.templates["search"], w, ctx)
+}
+
+// suggestHandler returns the titles of the pages the user may be typing, for
+// the search box and the links of the editor.
+func (app AppContext) suggestHandler(w http.ResponseWriter, r *http.Request) {
+	allowed := func(title string) bool {
+		return app.ACL.Allowed(currentUser(r), title, rightRead)
+	}
+
+	titles, err := app.Search.Suggest(r.URL.Query().Get("q"), allowed, suggestSize)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	if titles == nil {
+		titles = []string{}
+	}
+	writeJSON(w, http.StatusOK, titles)
 }
 
 func (app *AppContext) saveHandler(w http.ResponseWriter, r *http.Request) {
 	var title = normalizePath(mux.Vars(r)["title"])
+	if !app.authorize(w, r, title, rightEdit) {
+		return
+	}
 
 	body := r.FormValue("body")
 	message := r.FormValue("message")
@@ -327,22 +902,81 @@ func (app *AppContext) saveHandler(w http.ResponseWriter, r *http.Request) {
 		message = "Update " + title
 	}
 
-	if err := app.Storage.SetPageBody(title, body, message); err != nil {
-		log.Println(err)
+	_, _, err := app.Storage.SetPageBody(title, body, message, r.FormValue("base_revision"), authorOf(r))
+	if conflict, ok := err.(*EditConflict); ok {
+		ctx := EditContext{
+			PageContext:   app.pageContext(r, title, "conflict"),
+			BodySource:    conflict.Merged,
+			CommitMessage: r.FormValue("message"),
+			BaseRevision:  conflict.Revision,
+			Conflict:      conflict,
+			Edit:          true,
+		}
+		w.WriteHeader(http.StatusConflict)
+		app.templates["edit"].Execute(w, ctx)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), statusOf(err))
 		return
 	}
 
 	http.Redirect(w, r, "/"+title, http.StatusSeeOther)
 }
 
+func (app AppContext) uploadHandler(w http.ResponseWriter, r *http.Request) {
+	title := normalizePath(mux.Vars(r)["title"])
+	if !app.authorize(w, r, title, rightEdit) {
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	content, err := ioutil.ReadAll(file)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(content) > maxAttachmentSize {
+		http.Error(w, "Attachment too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" {
+		name = path.Base(strings.Replace(header.Filename, "\\", "/", -1))
+	}
+
+	message := r.FormValue("message")
+	if message == "" {
+		message = "Attach " + name + " to " + title
+	}
+
+	if err := app.Storage.SetAttachment(title, name, content, message, authorOf(r)); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	http.Redirect(w, r, "/"+escapeTitle(title)+"?action=attachments", http.StatusSeeOther)
+}
+
 func (app AppContext) viewHandler(w http.ResponseWriter, r *http.Request) {
 	var title = normalizePath(mux.Vars(r)["title"])
+	if !app.authorize(w, r, title, rightRead) {
+		return
+	}
+
 	revision := r.URL.Query().Get("revision")
 	format := r.URL.Query().Get("format")
 	body, err := app.Storage.PageBody(title, revision)
 	if err != nil {
 		if dirty, ok := err.(*DirtyWorkTree); ok {
-			w.Write([]byte(dirty.Error()))
+			http.Error(w, dirty.Error(), http.StatusServiceUnavailable)
 			return
 		}
 
@@ -351,28 +985,144 @@ func (app AppContext) viewHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if format == "raw" {
+		// Never let the browser sniff the source as HTML
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 		w.Write(body)
 		return
 	}
 
+	target, isRedirect := redirectTarget(body)
+	redirectedFrom := r.URL.Query().Get("redirectedfrom")
+	// Only follow one redirect, so that pages redirecting to each other
+	// don't loop
+	if isRedirect && revision == "" && redirectedFrom == "" && r.URL.Query().Get("redirect") != "no" {
+		http.Redirect(w, r, safeRedirect("/"+escapeTitle(target))+"?redirectedfrom="+url.QueryEscape(title), http.StatusFound)
+		return
+	}
+
 	if format == "printable" {
 		ctx := PrintableContext{
 			Title: title,
-			Body:  template.HTML(renderMarkdown(body)),
+			Body:  template.HTML(app.renderMarkdown(body, title, revision)),
 		}
 		app.templates["printable"].Execute(w, ctx)
 		return
 	}
 
 	ctx := ViewContext{
-		PageContext: PageContext{
-			Title:    title,
-			SubTitle: revision,
-		},
-		Body:     template.HTML(renderMarkdown(body)),
-		Revision: revision,
-		RawBody:  string(body),
+		PageContext:    app.pageContext(r, title, revision),
+		Body:           template.HTML(app.renderMarkdown(body, title, revision)),
+		Revision:       revision,
+		RawBody:        string(body),
+		RedirectedFrom: redirectedFrom,
+	}
+	if isRedirect {
+		ctx.RedirectTo = target
+	}
+	if !isPageTitle(redirectedFrom) {
+		ctx.RedirectedFrom = ""
+	}
+
+	if ctx.Attachments, err = app.Storage.Attachments(title, revision); err != nil {
+		log.Println(err)
 	}
 
 	app.templates["view"].Execute(w, ctx)
 }
+
+func (app AppContext) webhooksHandler(w http.ResponseWriter, r *http.Request) {
+	if !app.authorize(w, r, "", rightAdmin) {
+		return
+	}
+
+	ctx := WebhooksContext{
+		PageContext: app.pageContext(r, "Webhooks", ""),
+		Deliveries:  app.Webhooks.Deliveries(),
+	}
+
+	if r.Method == "POST" {
+		ctx.Source = r.FormValue("source")
+		if err := app.Webhooks.SetSource(ctx.Source); err != nil {
+			ctx.Error = err.Error()
+			w.WriteHeader(http.StatusBadRequest)
+			renderTemplate(app.templates["webhooks"], w, ctx)
+			return
+		}
+		ctx.Saved = true
+	}
+
+	source, err := app.Webhooks.Source()
+	if err != nil {
+		ctx.Error = err.Error()
+		renderError(app.templates["webhooks"], w, ctx, http.StatusInternalServerError)
+		return
+	}
+	ctx.Source = source
+
+	renderTemplate(app.templates["webhooks"], w, ctx)
+}
+
+// workTreeHandler lets administrators deal with the changes made to the data
+// directory outside of the wiki, which keep it from writing.
+func (app AppContext) workTreeHandler(w http.ResponseWriter, r *http.Request) {
+	if !app.authorize(w, r, "", rightAdmin) {
+		return
+	}
+
+	ctx := WorkTreeContext{
+		PageContext: app.pageContext(r, "Work Tree", ""),
+		Message:     externalEditMessage,
+	}
+	if app.WorkTree == nil {
+		ctx.Error = "Pages are not stored in a work tree"
+		renderError(app.templates["worktree"], w, ctx, http.StatusNotFound)
+		return
+	}
+
+	status := http.StatusOK
+	if r.Method == "POST" {
+		if message := strings.TrimSpace(r.FormValue("message")); message != "" {
+			ctx.Message = message
+		}
+
+		var err error
+		switch action := r.FormValue("action"); action {
+		case "commit":
+			err = app.WorkTree.CommitWorkTree(ctx.Message, authorOf(r))
+			ctx.Done = "Changes committed."
+		case "stash":
+			err = app.WorkTree.StashWorkTree(ctx.Message)
+			ctx.Done = "Changes stashed, run git stash apply in the data directory to get them back."
+		case "discard":
+			err = app.WorkTree.DiscardWorkTree()
+			ctx.Done = "Changes discarded."
+		default:
+			err = errors.New("Unknown action: " + action)
+			status = http.StatusBadRequest
+		}
+		if err != nil {
+			ctx.Done, ctx.Error = "", err.Error()
+			if status == http.StatusOK {
+				status = http.StatusInternalServerError
+			}
+		}
+	}
+
+	changes, err := app.WorkTree.WorkTreeChanges()
+	if err != nil {
+		ctx.Error = err.Error()
+		renderError(app.templates["worktree"], w, ctx, http.StatusInternalServerError)
+		return
+	}
+	ctx.Changes = changes
+	for _, change := range changes {
+		var diff template.HTML
+		if change.Diff != nil {
+			diff = renderDiff(change.Diff, diffViewUnified)
+		}
+		ctx.Diffs = append(ctx.Diffs, diff)
+	}
+
+	w.WriteHeader(status)
+	renderTemplate(app.templates["worktree"], w, ctx)
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestApp returns the handler of a wiki storing its pages in memory, with
+// the given pages, authentication mode and ACL file content.
+func newTestApp(t *testing.T, authMode string, acl string, admins []string, pages map[string]string) http.Handler {
+	dir := t.TempDir()
+	storage := NewMemoryStorage()
+	for title, body := range pages {
+		if _, _, err := storage.SetPageBody(title, body, "", "", Author{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	links, err := NewLinkIndex(storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	search, err := NewSearchIndex(links, filepath.Join(dir, "search-index.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(search.Close)
+	webhooks := NewWebhooks(search, filepath.Join(dir, "webhooks.json"))
+
+	auth, err := NewAuthenticator(authMode, NewUserStore(filepath.Join(dir, "users.json")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth.Header = "X-Forwarded-User"
+
+	aclFile := filepath.Join(dir, "acl.json")
+	if acl != "" {
+		if err := ioutil.WriteFile(aclFile, []byte(acl), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fallback := principalUsers
+	if authMode == authNone {
+		fallback = principalEveryone
+	}
+
+	templates, err := NewTemplates()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := AppContext{
+		Storage:   webhooks,
+		Links:     links,
+		Search:    search,
+		Auth:      auth,
+		ACL:       NewACL(aclFile, fallback, admins),
+		Webhooks:  webhooks,
+		sanitizer: NewSanitizer(),
+		templates: templates,
+	}
+	return app.handler()
+}
+
+// serve sends to h a request for target, posting form unless it is nil, with
+// the given headers and cookies.
+func serve(h http.Handler, method string, target string, form url.Values, header http.Header, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	var r *http.Request
+	if form != nil {
+		r = httptest.NewRequest(method, target, strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		r = httptest.NewRequest(method, target, nil)
+	}
+	for name, values := range header {
+		for _, value := range values {
+			r.Header.Add(name, value)
+		}
+	}
+	for _, cookie := range cookies {
+		r.AddCookie(cookie)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestRedirect(t *testing.T) {
+	h := newTestApp(t, authNone, "", nil, map[string]string{
+		"Old":       "#REDIRECT [[New]]\n",
+		"New":       "new\n",
+		"Loop A":    "#REDIRECT [[Loop B]]\n",
+		"Loop B":    "#REDIRECT [[Loop A]]\n",
+		"External":  "#REDIRECT [[/evil.example.com]]\n",
+		"Backslash": "#REDIRECT [[\\evil.example.com]]\n",
+	})
+
+	tests := []struct {
+		name     string
+		target   string
+		status   int
+		location string
+		excluded string // from the page
+	}{
+		{
+			name:     "redirect",
+			target:   "/Old",
+			status:   http.StatusFound,
+			location: "/New?redirectedfrom=Old",
+		},
+		{
+			name:   "redirect not followed",
+			target: "/Old?redirect=no",
+			status: http.StatusOK,
+		},
+		{
+			name:     "loop followed once",
+			target:   "/Loop%20A",
+			status:   http.StatusFound,
+			location: "/Loop%20B?redirectedfrom=Loop+A",
+		},
+		{
+			name:   "loop stops after one redirect",
+			target: "/Loop%20B?redirectedfrom=Loop+A",
+			status: http.StatusOK,
+		},
+		{
+			name:   "redirect to another site",
+			target: "/External",
+			status: http.StatusOK,
+		},
+		{
+			name:   "redirect to another site with a backslash",
+			target: "/Backslash",
+			status: http.StatusOK,
+		},
+		{
+			name:     "link back to another site",
+			target:   "/New?redirectedfrom=/evil.example.com",
+			status:   http.StatusOK,
+			excluded: "evil.example.com",
+		},
+	}
+
+	for _, test := range tests {
+		w := serve(h, "GET", test.target, nil, nil)
+		if w.Code != test.status {
+			t.Errorf("%s: status %d, want %d", test.name, w.Code, test.status)
+		}
+		if location := w.Header().Get("Location"); location != test.location {
+			t.Errorf("%s: location %q, want %q", test.name, location, test.location)
+		}
+		if test.excluded != "" && strings.Contains(w.Body.String(), test.excluded) {
+			t.Errorf("%s: page contains %q", test.name, test.excluded)
+		}
+	}
+}
+
+func TestACL(t *testing.T) {
+	acl := `[{"prefix": "Private/", "allow": {"read": ["alice"]}}]`
+	pages := map[string]string{"Home": "home\n", "Private/Notes": "notes\n"}
+
+	tests := []struct {
+		name     string
+		authMode string
+		admins   []string
+		user     string
+		method   string
+		target   string
+		status   int
+	}{
+		{"anonymous reads a page", authNone, nil, "", "GET", "/Home", http.StatusOK},
+		{"anonymous opens the ACL", authNone, nil, "", "GET", "/_/acl", http.StatusForbidden},
+		{"anonymous opens the webhooks", authNone, nil, "", "GET", "/_/webhooks", http.StatusForbidden},
+		{"anonymous must log in", authProxy, nil, "", "GET", "/Home", http.StatusUnauthorized},
+		{"user reads a page", authProxy, nil, "bob", "GET", "/Home", http.StatusOK},
+		{"user opens the ACL", authProxy, nil, "bob", "GET", "/_/acl", http.StatusForbidden},
+		{"user reads a private page", authProxy, nil, "bob", "GET", "/Private/Notes", http.StatusForbidden},
+		{"user reads a private page through the API", authProxy, nil, "bob", "GET", apiPrefix + "/pages/Private/Notes", http.StatusForbidden},
+		{"allowed user reads a private page", authProxy, nil, "alice", "GET", "/Private/Notes", http.StatusOK},
+		{"allowed user edits a private page", authProxy, nil, "alice", "GET", "/Private/Notes?action=edit", http.StatusForbidden},
+		{"admin opens the ACL", authProxy, []string{"carol"}, "carol", "GET", "/_/acl", http.StatusOK},
+		{"admin reads a private page", authProxy, []string{"carol"}, "carol", "GET", "/Private/Notes", http.StatusOK},
+		{"admin of no-auth wiki", authNone, []string{"*"}, "", "GET", "/_/acl", http.StatusOK},
+	}
+
+	for _, test := range tests {
+		h := newTestApp(t, test.authMode, acl, test.admins, pages)
+		header := http.Header{}
+		if test.user != "" {
+			header.Set("X-Forwarded-User", test.user)
+		}
+
+		w := serve(h, test.method, test.target, nil, header)
+		if w.Code != test.status {
+			t.Errorf("%s: status %d, want %d", test.name, w.Code, test.status)
+		}
+	}
+}
+
+func csrfCookieOf(t *testing.T, w *httptest.ResponseRecorder) *http.Cookie {
+	t.Helper()
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == csrfCookie {
+			return cookie
+		}
+	}
+	t.Fatal("No CSRF cookie")
+	return nil
+}
+
+func TestCSRF(t *testing.T) {
+	h := newTestApp(t, authNone, "", nil, map[string]string{"Home": "home\n", "Form": "form\n", "Header": "header\n"})
+	cookie := csrfCookieOf(t, serve(h, "GET", "/Home", nil, nil))
+	wrong := "0" + cookie.Value[1:]
+	if cookie.Value[0] == '0' {
+		wrong = "1" + cookie.Value[1:]
+	}
+
+	tests := []struct {
+		name    string
+		title   string
+		form    url.Values
+		header  http.Header
+		cookies []*http.Cookie
+		status  int
+	}{
+		{
+			name:   "no cookie and no token",
+			title:  "Home",
+			form:   url.Values{},
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "token without the cookie",
+			title:  "Home",
+			form:   url.Values{csrfField: {cookie.Value}},
+			status: http.StatusForbidden,
+		},
+		{
+			name:    "cookie without the token",
+			title:   "Home",
+			form:    url.Values{},
+			cookies: []*http.Cookie{cookie},
+			status:  http.StatusForbidden,
+		},
+		{
+			name:    "wrong token",
+			title:   "Home",
+			form:    url.Values{csrfField: {wrong}},
+			cookies: []*http.Cookie{cookie},
+			status:  http.StatusForbidden,
+		},
+		{
+			name:    "token in the form",
+			title:   "Form",
+			form:    url.Values{csrfField: {cookie.Value}},
+			cookies: []*http.Cookie{cookie},
+			status:  http.StatusSeeOther,
+		},
+		{
+			name:    "token in the header",
+			title:   "Header",
+			form:    url.Values{},
+			header:  http.Header{csrfHeader: {cookie.Value}},
+			cookies: []*http.Cookie{cookie},
+			status:  http.StatusSeeOther,
+		},
+	}
+
+	for _, test := range tests {
+		w := serve(h, "POST", "/"+test.title+"?action=delete", test.form, test.header, test.cookies...)
+		if w.Code != test.status {
+			t.Errorf("%s: status %d, want %d", test.name, w.Code, test.status)
+		}
+	}
+
+	for title, status := range map[string]int{"Home": http.StatusOK, "Form": http.StatusNotFound, "Header": http.StatusNotFound} {
+		if w := serve(h, "GET", apiPrefix+"/pages/"+title, nil, nil); w.Code != status {
+			t.Errorf("%s: status %d after the deletions, want %d", title, w.Code, status)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"log"
+	"sort"
+	"sync"
+)
+
+// LinkIndex is a PageStore keeping track of the links between the pages.
+type LinkIndex struct {
+	PageStore
+
+	mu        sync.RWMutex
+	head      string
+	links     map[string][]string            // source -> targets, for every page
+	backlinks map[string]map[string]struct{} // target -> sources
+}
+
+func NewLinkIndex(store PageStore) (*LinkIndex, error) {
+	index := &LinkIndex{PageStore: store}
+	if err := index.rebuild(); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+func (i *LinkIndex) rebuild() error {
+	head, err := i.PageStore.Head()
+	if err != nil {
+		return err
+	}
+
+	titles, err := i.PageStore.ListPages()
+	if err != nil {
+		return err
+	}
+
+	links := make(map[string][]string, len(titles))
+	for _, title := range titles {
+		body, err := i.PageStore.PageBody(title, head)
+		if err != nil {
+			return err
+		}
+		links[title] = pageLinks(string(body))
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.head = head
+	i.links = make(map[string][]string)
+	i.backlinks = make(map[string]map[string]struct{})
+	for title, targets := range links {
+		i.setLinks(title, targets)
+	}
+	return nil
+}
+
+// setLinks replaces the links of the page source. The caller must hold the
+// write lock.
+func (i *LinkIndex) setLinks(source string, targets []string) {
+	for _, target := range i.links[source] {
+		delete(i.backlinks[target], source)
+		if len(i.backlinks[target]) == 0 {
+			delete(i.backlinks, target)
+		}
+	}
+	delete(i.links, source)
+
+	if targets == nil {
+		return
+	}
+
+	i.links[source] = targets
+	for _, target := range targets {
+		if i.backlinks[target] == nil {
+			i.backlinks[target] = make(map[string]struct{})
+		}
+		i.backlinks[target][source] = struct{}{}
+	}
+}
+
+// update reindexes the pages titles after a write created revision.
+func (i *LinkIndex) update(revision string, titles ...string) {
+	parent, err := i.PageStore.Parent(revision)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	links := make(map[string][]string, len(titles))
+	for _, title := range titles {
+		body, err := i.PageStore.PageBody(title, revision)
+		if err != nil {
+			links[title] = nil
+			continue
+		}
+		links[title] = pageLinks(string(body))
+	}
+
+	i.mu.Lock()
+	fresh := i.head == parent
+	if fresh {
+		for title, targets := range links {
+			i.setLinks(title, targets)
+		}
+		i.head = revision
+	}
+	i.mu.Unlock()
+
+	if !fresh {
+		if err := i.rebuild(); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// refresh rebuilds the index if HEAD moved behind its back.
+func (i *LinkIndex) refresh() error {
+	head, err := i.PageStore.Head()
+	if err != nil {
+		return err
+	}
+
+	i.mu.RLock()
+	stale := head != i.head
+	i.mu.RUnlock()
+
+	if stale {
+		return i.rebuild()
+	}
+	return nil
+}
+
+// exists reports whether the page title exists, as of the last refresh.
+func (i *LinkIndex) exists(title string) bool {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	_, ok := i.links[title]
+	return ok
+}
+
+// Backlinks returns the titles of the pages linking to title.
+func (i *LinkIndex) Backlinks(title string) ([]string, error) {
+	if err := i.refresh(); err != nil {
+		return nil, err
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	titles := make([]string, 0, len(i.backlinks[title]))
+	for source := range i.backlinks[title] {
+		if source != title {
+			titles = append(titles, source)
+		}
+	}
+	sort.Strings(titles)
+	return titles, nil
+}
+
+func (i *LinkIndex) DeletePage(title string, author Author) (string, error) {
+	revision, err := i.PageStore.DeletePage(title, author)
+	if err != nil {
+		return "", err
+	}
+	i.update(revision, title)
+	return revision, nil
+}
+
+func (i *LinkIndex) RenamePage(title string, newTitle string, message string, redirect bool, updateLinks bool, author Author) (string, error) {
+	revision, err := i.PageStore.RenamePage(title, newTitle, message, redirect, updateLinks, author)
+	if err != nil {
+		return "", err
+	}
+	if updateLinks {
+		// Any page may have been rewritten
+		if err := i.rebuild(); err != nil {
+			log.Println(err)
+		}
+		return revision, nil
+	}
+	i.update(revision, title, newTitle)
+	return revision, nil
+}
+
+func (i *LinkIndex) SetPageBody(title string, body string, message string, baseRevision string, author Author) (string, bool, error) {
+	revision, created, err := i.PageStore.SetPageBody(title, body, message, baseRevision, author)
+	if err != nil {
+		return "", false, err
+	}
+	i.update(revision, title)
+	return revision, created, nil
+}
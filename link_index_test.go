@@ -0,0 +1,29 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLinkIndexCommitBehindItsBack(t *testing.T) {
+	storage := NewMemoryStorage()
+	index, err := NewLinkIndex(storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := storage.SetPageBody("A", "[[Target]]\n", "", "", Author{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := index.SetPageBody("B", "[[Target]]\n", "", "", Author{}); err != nil {
+		t.Fatal(err)
+	}
+
+	titles, err := index.Backlinks("Target")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"A", "B"}; !reflect.DeepEqual(titles, want) {
+		t.Errorf("Backlinks = %q, want %q", titles, want)
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var (
+	redirectPattern      = regexp.MustCompile(`^\s*#REDIRECT\s*\[\[([^\]|]+)(?:\|[^\]]*)?\]\]`)
+	inlineLinkPattern    = regexp.MustCompile(`\]\(\s*<?(/[^)\s>]*)`)
+	referenceLinkPattern = regexp.MustCompile(`(?m)^ {0,3}\[[^\]]+\]:[ \t]*<?(/[^\s>]*)`)
+)
+
+// redirectStub returns the body of a page redirecting to title.
+func redirectStub(title string) string {
+	return "#REDIRECT [[" + title + "]]\n"
+}
+
+// redirectTarget returns the title a redirect page points to. Targets which
+// are not page titles, such as /example.com, are ignored.
+func redirectTarget(body []byte) (string, bool) {
+	m := redirectPattern.FindSubmatch(body)
+	if m == nil {
+		return "", false
+	}
+	target := strings.TrimSpace(string(m[1]))
+	if !isPageTitle(target) {
+		return "", false
+	}
+	return target, true
+}
+
+// isPageTitle reports whether title can name a page: once prefixed with a
+// slash, it must stay a path on this site.
+func isPageTitle(title string) bool {
+	return title != "" &&
+		!strings.HasPrefix(title, "/") &&
+		!strings.HasPrefix(title, "\\") &&
+		path.Clean(title) == title
+}
+
+func escapeTitle(title string) string {
+	return (&url.URL{Path: title}).EscapedPath()
+}
+
+// linkPatterns returns the regular expressions matching the links to the
+// page title, with what surrounds it in their two groups.
+func linkPatterns(title string) []*regexp.Regexp {
+	forms := []string{regexp.QuoteMeta(title)}
+	if escaped := escapeTitle(title); escaped != title {
+		forms = append(forms, regexp.QuoteMeta(escaped))
+	}
+	destination := "/(?:" + strings.Join(forms, "|") + ")"
+
+	return []*regexp.Regexp{
+		// Inline links: [label](/title#anchor "tooltip")
+		regexp.MustCompile(`(\]\(\s*<?)` + destination + `((?:[#?][^)\s>]*)?>?(?:\s+"[^"]*")?\s*\))`),
+		// Reference definitions: [id]: /title#anchor
+		regexp.MustCompile(`(?m)^( {0,3}\[[^\]]+\]:[ \t]*<?)` + destination + `((?:[#?]\S*)?>?(?:[ \t].*)?)$`),
+		// Wiki links: [[title]] and [[title|label]]
+		regexp.MustCompile(`(\[\[\s*)` + regexp.QuoteMeta(title) + `(\s*(?:\|[^\]]*)?\]\])`),
+	}
+}
+
+// rewriteLinks returns body with the links to the page from pointing to the
+// page to instead.
+func rewriteLinks(body string, from string, to string) string {
+	patterns := linkPatterns(from)
+	replacements := []string{"/" + escapeTitle(to), "/" + escapeTitle(to), to}
+
+	for i, pattern := range patterns {
+		replacement := "${1}" + strings.Replace(replacements[i], "$", "$$", -1) + "${2}"
+		body = pattern.ReplaceAllString(body, replacement)
+	}
+	return body
+}
+
+// pageLinks returns the titles of the pages body links to.
+func pageLinks(body string) []string {
+	seen := make(map[string]struct{})
+	titles := make([]string, 0)
+
+	add := func(title string, ok bool) {
+		if _, dup := seen[title]; ok && !dup {
+			seen[title] = struct{}{}
+			titles = append(titles, title)
+		}
+	}
+
+	for _, m := range wikiLinkPattern.FindAllStringSubmatch(body, -1) {
+		add(wikiLinkTitle(m[1]))
+	}
+	for _, pattern := range []*regexp.Regexp{inlineLinkPattern, referenceLinkPattern} {
+		for _, m := range pattern.FindAllStringSubmatch(body, -1) {
+			add(linkTitle(m[1]))
+		}
+	}
+	return titles
+}
+
+func wikiLinkTitle(target string) (string, bool) {
+	if i := strings.Index(target, "#"); i >= 0 {
+		target = target[:i]
+	}
+	target = strings.TrimSpace(target)
+	return target, target != ""
+}
+
+// linkTitle returns the title of the page the URL path link points to.
+func linkTitle(link string) (string, bool) {
+	if i := strings.IndexAny(link, "#?"); i >= 0 {
+		link = link[:i]
+	}
+	if strings.HasPrefix(link, "//") || link == "/_" || strings.HasPrefix(link, "/_/") {
+		return "", false
+	}
+
+	title, err := url.PathUnescape(strings.TrimPrefix(link, "/"))
+	if err != nil {
+		return "", false
+	}
+	return normalizePath(title), true
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"html"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/russross/blackfriday"
+)
+
+const (
+	attachmentScheme = "attachment:"
+	wikiScheme       = "wiki:"
+)
+
+var (
+	fencePattern    = regexp.MustCompile("^ {0,3}(```|~~~)")
+	headerIDPattern = regexp.MustCompile(`^[\w-]+$`)
+	wikiLinkPattern = regexp.MustCompile(`\[\[([^\[\]|]+)(?:\|([^\[\]]*))?\]\]`)
+)
+
+// wikiRenderer resolves the wiki specific link destinations before handing
+// them to the HTML renderer.
+type wikiRenderer struct {
+	blackfriday.Renderer
+	title    string
+	revision string
+	links    *LinkIndex
+}
+
+// attachmentURL returns the URL serving the attachment name of the page title.
+func attachmentURL(title string, name string, revision string) string {
+	u := "/" + escapeTitle(title) + "?action=attachment&name=" + url.QueryEscape(name)
+	if revision != "" {
+		u += "&revision=" + url.QueryEscape(revision)
+	}
+	return u
+}
+
+func (r *wikiRenderer) resolve(link []byte) []byte {
+	if !bytes.HasPrefix(link, []byte(attachmentScheme)) {
+		return link
+	}
+
+	name := strings.TrimPrefix(string(link), attachmentScheme)
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+	return []byte(attachmentURL(r.title, name, r.revision))
+}
+
+func (r *wikiRenderer) Image(out *bytes.Buffer, link []byte, title []byte, alt []byte) {
+	r.Renderer.Image(out, r.resolve(link), title, alt)
+}
+
+func (r *wikiRenderer) Link(out *bytes.Buffer, link []byte, title []byte, content []byte) {
+	if bytes.HasPrefix(link, []byte(wikiScheme)) {
+		r.wikiLink(out, strings.TrimPrefix(string(link), wikiScheme), content)
+		return
+	}
+	r.Renderer.Link(out, r.resolve(link), title, content)
+}
+
+// wikiLink renders a link to a page. Links to missing pages lead to their
+// edit form.
+func (r *wikiRenderer) wikiLink(out *bytes.Buffer, target string, content []byte) {
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		target = unescaped
+	}
+
+	page, anchor := target, ""
+	if i := strings.Index(target, "#"); i >= 0 {
+		page, anchor = target[:i], target[i:]
+	}
+	if page == "" {
+		page = r.title
+	}
+
+	href := "/" + escapeTitle(page)
+	class := ""
+	if r.links.exists(page) {
+		href += anchor
+	} else {
+		href += "?action=edit"
+		class = ` class="missing-page"`
+	}
+
+	out.WriteString(`<a href="` + html.EscapeString(href) + `"` + class + `>`)
+	out.Write(content)
+	out.WriteString("</a>")
+}
+
+// expandWikiLinks turns the [[Title]] links of source outside of code into
+// Markdown links.
+func expandWikiLinks(source []byte) []byte {
+	var out bytes.Buffer
+	fence := ""
+
+	for _, line := range strings.SplitAfter(string(source), "\n") {
+		if m := fencePattern.FindStringSubmatch(line); m != nil {
+			if fence == "" {
+				fence = m[1]
+			} else if fence == m[1] {
+				fence = ""
+			}
+			out.WriteString(line)
+			continue
+		}
+
+		if fence != "" {
+			out.WriteString(line)
+			continue
+		}
+
+		out.WriteString(expandLineWikiLinks(line))
+	}
+	return out.Bytes()
+}
+
+func expandLineWikiLinks(line string) string {
+	var out strings.Builder
+
+	for line != "" {
+		start := strings.Index(line, "`")
+		if start < 0 {
+			out.WriteString(replaceWikiLinks(line))
+			break
+		}
+		out.WriteString(replaceWikiLinks(line[:start]))
+		line = line[start:]
+
+		// Skip the code span closed by a backtick run of the same length
+		n := len(line) - len(strings.TrimLeft(line, "`"))
+		ticks := line[:n]
+		end := -1
+		for i := n; i < len(line); {
+			j := strings.Index(line[i:], ticks)
+			if j < 0 {
+				break
+			}
+			j += i
+			k := j + n
+			for k < len(line) && line[k] == '`' {
+				k++
+			}
+			if k-j == n {
+				end = k
+				break
+			}
+			i = k
+		}
+		if end < 0 {
+			end = n
+		}
+		out.WriteString(line[:end])
+		line = line[end:]
+	}
+	return out.String()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `[`, `\[`, `]`, `\]`)
+
+func replaceWikiLinks(text string) string {
+	return wikiLinkPattern.ReplaceAllStringFunc(text, func(link string) string {
+		m := wikiLinkPattern.FindStringSubmatch(link)
+		title := strings.TrimSpace(m[1])
+		label := strings.TrimSpace(m[2])
+		if label == "" {
+			label = title
+		}
+
+		target := strings.NewReplacer("(", "%28", ")", "%29").Replace(url.PathEscape(title))
+		return "[" + labelEscaper.Replace(label) + "](" + wikiScheme + target + ")"
+	})
+}
+
+// NewSanitizer returns the allowlist policy applied to rendered pages: the
+// policy for user generated content, plus what the renderer itself emits.
+func NewSanitizer() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+	policy.AllowElements("nav")
+	policy.AllowAttrs("id").Matching(headerIDPattern).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^missing-page$`)).OnElements("a")
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	policy.RequireNoFollowOnLinks(false)
+	return policy
+}
+
+// renderMarkdown renders the source of the page title at revision.
+func (app AppContext) renderMarkdown(source []byte, title string, revision string) []byte {
+	flags := 0
+	flags |= blackfriday.HTML_TOC
+	flags |= blackfriday.HTML_SAFELINK
+
+	extensions := 0
+	extensions |= blackfriday.EXTENSION_TABLES
+	extensions |= blackfriday.EXTENSION_FENCED_CODE
+	extensions |= blackfriday.EXTENSION_AUTOLINK
+	extensions |= blackfriday.EXTENSION_STRIKETHROUGH
+	extensions |= blackfriday.EXTENSION_HEADER_IDS
+	extensions |= blackfriday.EXTENSION_AUTO_HEADER_IDS
+
+	if err := app.Links.refresh(); err != nil {
+		log.Println(err)
+	}
+
+	renderer := &wikiRenderer{
+		Renderer: blackfriday.HtmlRenderer(flags, "", ""),
+		title:    title,
+		revision: revision,
+		links:    app.Links,
+	}
+	out := blackfriday.Markdown(expandWikiLinks(source), renderer, extensions)
+
+	// Raw HTML is only trusted when sanitization is disabled
+	if app.sanitizer == nil {
+		return out
+	}
+	return app.sanitizer.SanitizeBytes(out)
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMissingPages(t *testing.T) {
+	storage := NewMemoryStorage()
+	if _, _, err := storage.SetPageBody("Exists", "exists\n", "", "", Author{}); err != nil {
+		t.Fatal(err)
+	}
+	links, err := NewLinkIndex(storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := AppContext{Storage: links, Links: links}
+
+	out := string(app.renderMarkdown([]byte("[[Exists]] [[Missing]]\n"), "Home", ""))
+	for _, want := range []string{`<a href="/Exists">`, `<a href="/Missing?action=edit" class="missing-page">`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("renderMarkdown = %q, want %q in it", out, want)
+		}
+	}
+
+	// Created behind the back of the index
+	if _, _, err := storage.SetPageBody("Missing", "found\n", "", "", Author{}); err != nil {
+		t.Fatal(err)
+	}
+	out = string(app.renderMarkdown([]byte("[[Missing]]\n"), "Home", ""))
+	if want := `<a href="/Missing">`; !strings.Contains(out, want) {
+		t.Errorf("renderMarkdown = %q, want %q in it", out, want)
+	}
+}
@@ -0,0 +1,487 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStorage is a PageStore keeping every revision in memory. Its content
+// is lost when the process exits.
+type MemoryStorage struct {
+	mu        sync.RWMutex
+	revisions []memoryRevision
+}
+
+type memoryRevision struct {
+	commit      Commit
+	date        time.Time
+	pages       map[string]string
+	attachments map[string][]byte // title/name -> content
+	renames     map[string]string // new title -> old title
+}
+
+func NewMemoryStorage() *MemoryStorage {
+	s := &MemoryStorage{}
+	s.commit(memoryRevision{
+		pages:       make(map[string]string),
+		attachments: make(map[string][]byte),
+	}, "Initial commit", Author{})
+	return s
+}
+
+// commit records r as a new revision and returns its ID. The caller must hold
+// the write lock.
+func (s *MemoryStorage) commit(r memoryRevision, message string, author Author) string {
+	parent := ""
+	if len(s.revisions) > 0 {
+		parent = s.head().commit.ID
+	}
+
+	id := fmt.Sprintf("%x", sha1.Sum([]byte(fmt.Sprintf("%s\n%d\n%s", parent, len(s.revisions), message))))
+	r.date = time.Now()
+	r.commit = Commit{
+		ID:          id,
+		Date:        r.date.Format(gitDateFormat),
+		Message:     message,
+		AuthorName:  author.Name,
+		AuthorEmail: author.Email,
+	}
+	s.revisions = append(s.revisions, r)
+	return id
+}
+
+// next returns a copy of the head revision to be modified and committed.
+func (s *MemoryStorage) next() memoryRevision {
+	head := s.head()
+
+	attachments := make(map[string][]byte, len(head.attachments))
+	for key, content := range head.attachments {
+		attachments[key] = content
+	}
+
+	return memoryRevision{
+		pages:       copyPages(head.pages),
+		attachments: attachments,
+	}
+}
+
+// pageAttachments returns the attachments of the page title in r by name.
+func pageAttachments(r memoryRevision, title string) map[string][]byte {
+	attachments := make(map[string][]byte)
+	for key, content := range r.attachments {
+		if path.Dir(key) == title {
+			attachments[path.Base(key)] = content
+		}
+	}
+	return attachments
+}
+
+func sameAttachments(a map[string][]byte, b map[string][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, content := range a {
+		if other, ok := b[name]; !ok || !bytes.Equal(content, other) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *MemoryStorage) head() memoryRevision {
+	return s.revisions[len(s.revisions)-1]
+}
+
+// snapshot returns the revisions recorded so far. Revisions are never
+// modified once recorded, so they can be read without holding the lock.
+func (s *MemoryStorage) snapshot() []memoryRevision {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.revisions[:len(s.revisions):len(s.revisions)]
+}
+
+func findRevision(revisions []memoryRevision, revision string) (memoryRevision, error) {
+	if revision == "" || revision == "HEAD" {
+		return revisions[len(revisions)-1], nil
+	}
+
+	for _, r := range revisions {
+		if strings.HasPrefix(r.commit.ID, revision) {
+			return r, nil
+		}
+	}
+	return memoryRevision{}, errors.New("Unknown revision " + revision)
+}
+
+func copyPages(pages map[string]string) map[string]string {
+	c := make(map[string]string, len(pages))
+	for title, body := range pages {
+		c[title] = body
+	}
+	return c
+}
+
+func (s *MemoryStorage) Attachment(title string, name string, revision string) ([]byte, error) {
+	r, err := findRevision(s.snapshot(), revision)
+	if err != nil {
+		return nil, err
+	}
+
+	content, ok := r.attachments[path.Join(title, name)]
+	if !ok || !validAttachmentName(name) {
+		return nil, errors.New("Attachment not found: " + name)
+	}
+	return content, nil
+}
+
+func (s *MemoryStorage) Attachments(title string, revision string) ([]string, error) {
+	r, err := findRevision(s.snapshot(), revision)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0)
+	for name := range pageAttachments(r, title) {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func (s *MemoryStorage) DeleteAttachment(title string, name string, message string, author Author) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.next()
+	key := path.Join(title, name)
+	if _, ok := r.attachments[key]; !ok || !validAttachmentName(name) {
+		return errors.New("Attachment not found: " + name)
+	}
+	delete(r.attachments, key)
+
+	s.commit(r, message, author)
+	return nil
+}
+
+func (s *MemoryStorage) DeletePage(title string, author Author) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.next()
+	if _, ok := r.pages[title]; !ok {
+		return "", &PageNotFound{Title: title}
+	}
+	delete(r.pages, title)
+	for name := range pageAttachments(r, title) {
+		delete(r.attachments, path.Join(title, name))
+	}
+
+	return s.commit(r, "Delete "+title, author), nil
+}
+
+func (s *MemoryStorage) Diff(title string, body string) (*PageDiff, error) {
+	head, _ := findRevision(s.snapshot(), "HEAD")
+	return newPageDiff("a/"+title, "b/"+title, head.pages[title], body), nil
+}
+
+func (s *MemoryStorage) DiffRevisions(title string, from string, to string) (*PageDiff, error) {
+	history, err := s.History(title)
+	if err != nil {
+		return nil, err
+	}
+	return s.DiffPages(titleAt(history, title, from), from, titleAt(history, title, to), to)
+}
+
+func (s *MemoryStorage) DiffPages(fromTitle string, from string, toTitle string, to string) (*PageDiff, error) {
+	revisions := s.snapshot()
+	bodies := make([]string, 2)
+	titles := []string{fromTitle, toTitle}
+	for i, revision := range []string{from, to} {
+		if revision == "" {
+			continue
+		}
+
+		r, err := findRevision(revisions, revision)
+		if err != nil {
+			return nil, err
+		}
+		bodies[i] = r.pages[titles[i]]
+	}
+
+	fromName := "a/" + titles[0]
+	if from == "" {
+		fromName = "/dev/null"
+	}
+	return newPageDiff(fromName, "b/"+titles[1], bodies[0], bodies[1]), nil
+}
+
+func (s *MemoryStorage) Head() (string, error) {
+	head, _ := findRevision(s.snapshot(), "HEAD")
+	return head.commit.ID, nil
+}
+
+func (s *MemoryStorage) History(title string) ([]Commit, error) {
+	revisions := s.snapshot()
+
+	commits := make([]Commit, 0)
+	for i := len(revisions) - 1; i > 0; i-- {
+		body, ok := revisions[i].pages[title]
+		prevBody, prevOk := revisions[i-1].pages[title]
+
+		attachments := pageAttachments(revisions[i], title)
+		prevAttachments := pageAttachments(revisions[i-1], title)
+
+		if ok == prevOk && body == prevBody && sameAttachments(attachments, prevAttachments) {
+			continue
+		}
+
+		commit := revisions[i].commit
+		commit.Title = title
+		commit.Delete = !ok
+
+		if from, renamed := revisions[i].renames[title]; renamed && ok {
+			commit.RenamedFrom = from
+			title = from
+		}
+
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+func (s *MemoryStorage) ListDeletedPages() ([]string, error) {
+	revisions := s.snapshot()
+
+	head := revisions[len(revisions)-1].pages
+	titles := make(map[string]struct{})
+	for _, r := range revisions {
+		for title := range r.pages {
+			if _, ok := head[title]; !ok {
+				titles[title] = struct{}{}
+			}
+		}
+	}
+
+	_titles := make([]string, 0, len(titles))
+	for title := range titles {
+		_titles = append(_titles, title)
+	}
+	sort.Strings(_titles)
+
+	return _titles, nil
+}
+
+func (s *MemoryStorage) ListPages() ([]string, error) {
+	head, _ := findRevision(s.snapshot(), "HEAD")
+
+	titles := make([]string, 0, len(head.pages))
+	for title := range head.pages {
+		titles = append(titles, title)
+	}
+	sort.Strings(titles)
+
+	return titles, nil
+}
+
+func (s *MemoryStorage) PageBody(title string, revision string) ([]byte, error) {
+	r, err := findRevision(s.snapshot(), revision)
+	if err != nil {
+		return nil, err
+	}
+
+	body, ok := r.pages[title]
+	if !ok {
+		return nil, &PageNotFound{Title: title}
+	}
+	return []byte(body), nil
+}
+
+func (s *MemoryStorage) Parent(revision string) (string, error) {
+	revisions := s.snapshot()
+	r, err := findRevision(revisions, revision)
+	if err != nil {
+		return "", err
+	}
+	for n := 1; n < len(revisions); n++ {
+		if revisions[n].commit.ID == r.commit.ID {
+			return revisions[n-1].commit.ID, nil
+		}
+	}
+	return "", nil
+}
+
+func (s *MemoryStorage) RecentChanges(filter ChangeFilter) ([]Change, error) {
+	revisions := s.snapshot()
+
+	collector := newChangeCollector(filter)
+	for i := len(revisions) - 1; i > 0; i-- {
+		r, parent := revisions[i], revisions[i-1]
+
+		match, done := filter.matchCommit(r.date, Author{Name: r.commit.AuthorName, Email: r.commit.AuthorEmail})
+		if done {
+			break
+		}
+		if !match {
+			continue
+		}
+
+		titles := make([]string, 0)
+		for title, body := range r.pages {
+			if parentBody, ok := parent.pages[title]; !ok || parentBody != body {
+				titles = append(titles, title)
+			}
+		}
+		for title := range parent.pages {
+			if _, ok := r.pages[title]; !ok {
+				titles = append(titles, title)
+			}
+		}
+		sort.Strings(titles)
+
+		for _, title := range titles {
+			change := Change{Commit: r.commit, Kind: changeModify, Parent: parent.commit.ID}
+			change.Title = title
+
+			if _, ok := r.pages[title]; !ok {
+				change.Kind = changeDelete
+				change.Delete = true
+			} else if _, ok := parent.pages[title]; !ok {
+				change.Kind = changeAdd
+				change.RenamedFrom = r.renames[title]
+			}
+
+			if collector.add(change) {
+				return collector.changes, nil
+			}
+		}
+	}
+	return collector.changes, nil
+}
+
+func (s *MemoryStorage) RenamePage(title string, newTitle string, message string, redirect bool, updateLinks bool, author Author) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if newTitle == "" || newTitle == title {
+		return "", errors.New("Invalid title: " + newTitle)
+	}
+
+	r := s.next()
+	body, ok := r.pages[title]
+	if !ok {
+		return "", &PageNotFound{Title: title}
+	}
+	if _, ok := r.pages[newTitle]; ok {
+		return "", errors.New("Page already exists: " + newTitle)
+	}
+
+	if updateLinks {
+		for t, b := range r.pages {
+			if t != title {
+				r.pages[t] = rewriteLinks(b, title, newTitle)
+			}
+		}
+	}
+
+	r.pages[newTitle] = body
+	if redirect {
+		r.pages[title] = redirectStub(newTitle)
+	} else {
+		delete(r.pages, title)
+	}
+
+	for name, content := range pageAttachments(r, title) {
+		delete(r.attachments, path.Join(title, name))
+		r.attachments[path.Join(newTitle, name)] = content
+	}
+
+	r.renames = map[string]string{newTitle: title}
+	return s.commit(r, message, author), nil
+}
+
+func (s *MemoryStorage) Search(q string, options SearchOptions) ([]PageSearchResult, error) {
+	re, err := options.pattern(q)
+	if err != nil {
+		return nil, err
+	}
+
+	head, _ := findRevision(s.snapshot(), "HEAD")
+	pages := head.pages
+
+	titles := make([]string, 0, len(pages))
+	for title := range pages {
+		if strings.HasPrefix(title, options.Prefix) {
+			titles = append(titles, title)
+		}
+	}
+	sort.Strings(titles)
+
+	searchResults := make([]PageSearchResult, 0)
+	for _, title := range titles {
+		if result, ok := matchPattern(re, title, pages[title]); ok {
+			searchResults = append(searchResults, result)
+		}
+	}
+
+	return searchResults, nil
+}
+
+func (s *MemoryStorage) SetAttachment(title string, name string, content []byte, message string, author Author) error {
+	if !validAttachmentName(name) {
+		return errors.New("Invalid attachment name: " + name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.next()
+	if _, ok := r.pages[title]; !ok {
+		return &PageNotFound{Title: title}
+	}
+	r.attachments[path.Join(title, name)] = content
+
+	s.commit(r, message, author)
+	return nil
+}
+
+func (s *MemoryStorage) SetPageBody(title string, body string, message string, baseRevision string, author Author) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if baseRevision != "" {
+		base, err := findRevision(s.revisions, baseRevision)
+		if err != nil {
+			return "", false, err
+		}
+
+		current := s.head().pages[title]
+		if current != base.pages[title] {
+			merged, ok := merge3(base.pages[title], body, current)
+			if !ok {
+				return "", false, &EditConflict{
+					Title:    title,
+					Revision: s.head().commit.ID,
+					Yours:    body,
+					Current:  current,
+					Merged:   merged,
+				}
+			}
+			body = merged
+		}
+	}
+
+	r := s.next()
+	_, exists := r.pages[title]
+	r.pages[title] = body
+
+	return s.commit(r, message, author), !exists, nil
+}
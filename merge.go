@@ -0,0 +1,150 @@
+package main
+
+import (
+	"strings"
+)
+
+const (
+	conflictStartMarker  = "<<<<<<< yours"
+	conflictMiddleMarker = "======="
+	conflictEndMarker    = ">>>>>>> current"
+)
+
+// EditConflict is returned when a page was modified since the revision an
+// edit started from and the changes could not be merged.
+type EditConflict struct {
+	Title    string
+	Revision string
+	Yours    string
+	Current  string
+	Merged   string
+}
+
+func (e *EditConflict) Error() string {
+	return "Page " + e.Title + " was modified by someone else"
+}
+
+// mergeChunk replaces the base lines [Start, End) with Lines.
+type mergeChunk struct {
+	Start int
+	End   int
+	Lines []string
+}
+
+func mergeChunks(lines []diffLine) []mergeChunk {
+	chunks := make([]mergeChunk, 0)
+
+	var chunk *mergeChunk
+	base := 0
+	for _, line := range lines {
+		if line.Op == diffEqual {
+			if chunk != nil {
+				chunks = append(chunks, *chunk)
+				chunk = nil
+			}
+			base++
+			continue
+		}
+
+		if chunk == nil {
+			chunk = &mergeChunk{Start: base, End: base}
+		}
+		if line.Op == diffDelete {
+			base++
+			chunk.End = base
+		} else {
+			chunk.Lines = append(chunk.Lines, line.Text)
+		}
+	}
+	if chunk != nil {
+		chunks = append(chunks, *chunk)
+	}
+	return chunks
+}
+
+// applyChunks returns the base lines [start, end) with chunks applied.
+func applyChunks(base []string, start int, end int, chunks []mergeChunk) []string {
+	lines := make([]string, 0)
+	for _, chunk := range chunks {
+		lines = append(lines, base[start:chunk.Start]...)
+		lines = append(lines, chunk.Lines...)
+		start = chunk.End
+	}
+	return append(lines, base[start:end]...)
+}
+
+// merge3 merges the changes made to base in yours and current. ok is false if
+// some conflict.
+func merge3(base string, yours string, current string) (merged string, ok bool) {
+	baseLines := splitLines(base)
+	ours := mergeChunks(diffLines(baseLines, splitLines(yours)))
+	theirs := mergeChunks(diffLines(baseLines, splitLines(current)))
+
+	lines := make([]string, 0)
+	ok = true
+	pos := 0
+
+	for len(ours) > 0 || len(theirs) > 0 {
+		var groupOurs, groupTheirs []mergeChunk
+
+		var start int
+		if len(theirs) == 0 || (len(ours) > 0 && ours[0].Start <= theirs[0].Start) {
+			start = ours[0].Start
+		} else {
+			start = theirs[0].Start
+		}
+		end := start
+
+		// Gather the chunks of both sides overlapping or touching each other
+		for {
+			if len(ours) > 0 && ours[0].Start <= end {
+				if ours[0].End > end {
+					end = ours[0].End
+				}
+				groupOurs = append(groupOurs, ours[0])
+				ours = ours[1:]
+			} else if len(theirs) > 0 && theirs[0].Start <= end {
+				if theirs[0].End > end {
+					end = theirs[0].End
+				}
+				groupTheirs = append(groupTheirs, theirs[0])
+				theirs = theirs[1:]
+			} else {
+				break
+			}
+		}
+
+		lines = append(lines, baseLines[pos:start]...)
+		pos = end
+
+		oursLines := applyChunks(baseLines, start, end, groupOurs)
+		theirsLines := applyChunks(baseLines, start, end, groupTheirs)
+
+		switch {
+		case len(groupTheirs) == 0:
+			lines = append(lines, oursLines...)
+		case len(groupOurs) == 0:
+			lines = append(lines, theirsLines...)
+		case strings.Join(oursLines, "\n") == strings.Join(theirsLines, "\n"):
+			lines = append(lines, oursLines...)
+		default:
+			ok = false
+			lines = append(lines, conflictStartMarker)
+			lines = append(lines, oursLines...)
+			lines = append(lines, conflictMiddleMarker)
+			lines = append(lines, theirsLines...)
+			lines = append(lines, conflictEndMarker)
+		}
+	}
+	lines = append(lines, baseLines[pos:]...)
+
+	if len(lines) == 0 {
+		return "", ok
+	}
+
+	merged = strings.Join(lines, "\n")
+	if strings.HasSuffix(yours, "\n") || (yours == "" && strings.HasSuffix(current, "\n")) {
+		merged += "\n"
+	}
+	return merged, ok
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestMerge3(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    string
+		yours   string
+		current string
+		want    string
+		ok      bool
+	}{
+		{
+			name:    "no changes",
+			base:    "a\nb\nc\n",
+			yours:   "a\nb\nc\n",
+			current: "a\nb\nc\n",
+			want:    "a\nb\nc\n",
+			ok:      true,
+		},
+		{
+			name:    "only yours changed",
+			base:    "a\nb\nc\n",
+			yours:   "a\nB\nc\n",
+			current: "a\nb\nc\n",
+			want:    "a\nB\nc\n",
+			ok:      true,
+		},
+		{
+			name:    "only current changed",
+			base:    "a\nb\nc\n",
+			yours:   "a\nb\nc\n",
+			current: "a\nb\nC\n",
+			want:    "a\nb\nC\n",
+			ok:      true,
+		},
+		{
+			name:    "edits apart",
+			base:    "a\nb\nc\nd\ne\n",
+			yours:   "A\nb\nc\nd\ne\n",
+			current: "a\nb\nc\nd\nE\n",
+			want:    "A\nb\nc\nd\nE\n",
+			ok:      true,
+		},
+		{
+			name:    "edits one line apart",
+			base:    "a\nb\nc\n",
+			yours:   "A\nb\nc\n",
+			current: "a\nb\nC\n",
+			want:    "A\nb\nC\n",
+			ok:      true,
+		},
+		{
+			name:    "adjacent edits conflict",
+			base:    "a\nb\nc\nd\n",
+			yours:   "a\nB\nc\nd\n",
+			current: "a\nb\nC\nd\n",
+			want:    "a\n<<<<<<< yours\nB\nc\n=======\nb\nC\n>>>>>>> current\nd\n",
+		},
+		{
+			name:    "same change on both sides",
+			base:    "a\nb\nc\n",
+			yours:   "a\nB\nc\n",
+			current: "a\nB\nc\n",
+			want:    "a\nB\nc\n",
+			ok:      true,
+		},
+		{
+			name:    "same insertion on both sides",
+			base:    "a\nc\n",
+			yours:   "a\nb\nc\n",
+			current: "a\nb\nc\n",
+			want:    "a\nb\nc\n",
+			ok:      true,
+		},
+		{
+			name:    "different edits of the same line",
+			base:    "a\nb\nc\n",
+			yours:   "a\nyours\nc\n",
+			current: "a\ncurrent\nc\n",
+			want:    "a\n<<<<<<< yours\nyours\n=======\ncurrent\n>>>>>>> current\nc\n",
+		},
+		{
+			name:    "insertion at EOF and edit at the start",
+			base:    "a\nb\nc\n",
+			yours:   "a\nb\nc\nd\n",
+			current: "A\nb\nc\n",
+			want:    "A\nb\nc\nd\n",
+			ok:      true,
+		},
+		{
+			name:    "different insertions at EOF",
+			base:    "a\nb\n",
+			yours:   "a\nb\nyours\n",
+			current: "a\nb\ncurrent\n",
+			want:    "a\nb\n<<<<<<< yours\nyours\n=======\ncurrent\n>>>>>>> current\n",
+		},
+		{
+			name:    "insertion at EOF without a final newline",
+			base:    "a\nb",
+			yours:   "a\nb\nc",
+			current: "A\nb",
+			want:    "A\nb\nc",
+			ok:      true,
+		},
+		{
+			name:    "deletion in yours, edit in current",
+			base:    "a\nb\nc\n",
+			yours:   "a\nc\n",
+			current: "a\nB\nc\n",
+			want:    "a\n<<<<<<< yours\n=======\nB\n>>>>>>> current\nc\n",
+		},
+		{
+			name:    "edit in yours, deletion in current",
+			base:    "a\nb\nc\n",
+			yours:   "a\nB\nc\n",
+			current: "a\nc\n",
+			want:    "a\n<<<<<<< yours\nB\n=======\n>>>>>>> current\nc\n",
+		},
+		{
+			name:    "deletion and edit apart",
+			base:    "a\nb\nc\nd\ne\n",
+			yours:   "b\nc\nd\ne\n",
+			current: "a\nb\nc\nd\nE\n",
+			want:    "b\nc\nd\nE\n",
+			ok:      true,
+		},
+		{
+			name:    "same deletion on both sides",
+			base:    "a\nb\nc\n",
+			yours:   "a\nc\n",
+			current: "a\nc\n",
+			want:    "a\nc\n",
+			ok:      true,
+		},
+		{
+			name:    "everything deleted",
+			base:    "a\nb\n",
+			yours:   "",
+			current: "",
+			want:    "",
+			ok:      true,
+		},
+		{
+			name:    "empty base",
+			base:    "",
+			yours:   "a\n",
+			current: "",
+			want:    "a\n",
+			ok:      true,
+		},
+	}
+
+	for _, test := range tests {
+		merged, ok := merge3(test.base, test.yours, test.current)
+		if merged != test.want || ok != test.ok {
+			t.Errorf("%s: merge3 = %q, %v; want %q, %v", test.name, merged, ok, test.want, test.ok)
+		}
+	}
+}
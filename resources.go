@@ -123,160 +123,299 @@ var _esc_data = map[string]*_esc_file{
 	"/static/jquery.hotkeys.js": {
 		local: "resources/static/jquery.hotkeys.js",
 		size:  5009,
-		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\x94Xms\xe3\xb6\x11\xfe\xee_\xb1Ǧ'\xe9DSo>\xbf\xc6i\xef.\x99&m\x12\xa7\xcdMf::5\x85HP\x84M\x11,\x00\x9eN\x8d\xdd\xdf\xde]\xf0\x05\xa0\xac\xb9\x99\xf8\x83\xc9g\xb1\xd8}v\xb1\x00\x96\x9a\xbc\xba\u05f9(\f\xac\x95\xdci\xae\xae\xc1\xa8\x8a\xbf\x9a\x9ct\x03\xf7\xff" +
-			"\xa9\xb8\xdawr\x1c8\x81Wp\xffw\x92·\xd2<\U0003d19f\xf2j#\n\x1ax'˽\x12\x9b\xcc\xc0|:\x9b\x86\xf0W\x99\x15\xf0\x0f\xaeņF\xbf\xaeX\x0e\xb9\x88y\xa1y\x02U\x91p\x05&\xe3\xf0\xc3w\xefA*\xf8\xcbO\xdf\xc3/\\i!\v\x98\xb7z:\u00994\xf9-\xb3\x93J\x1c\xa49\xa5\xf5\t\xeb=" +
-			"\xbc\xffo\x85\\\xde2\x05\xff\x94q\xc6\xf6פ\x9d\x19S^O&\x1ba\xb2j\x1d\xc5r;1\xa4\xb6\xdeO\xb2\x9atc\xf5\x0eي\x02i\x89\x843\xb4f'\xbf\x15E\xb1\x87_\xe0M\xd8\xda\xd9\xedv\x91,yq\xaf\xad-\x1d+Q\x1a=\xe1\x1fy\x81\x0f\xb4\xb7\x96L%\xbf\xeaL*\x13W(B3]\xb6\xee\n\x0ez" +
-			"\xcb\xf2\x1c\x90^\xb1\xe1 \xf45\x14r\a6yLa0LSp\x18\x8c\\\xdf\xf3\xd8\xc0ov\xec\x1a\x06Q\x14\r\xe0\x89\xac\xfc`Ӻ\xe6Pi\x9eVy\b\xbb\x8c\x17\xb0\x97\x15\xec\x18.\x94\x91\xd6\nh\xb9\xe5 1C\n\x12f\x18\xc9QG\x01zNr\xaej^ô*b\x83y\x1e\xd6+9\x82\xdfNN\xa0Y\xd6" +
-			"\xa8\xc9\x10ܢ\x14\xf0\xefc\xbd&\xd7\x10L\xa3\xcb <\xb1B]\xf2X\xb0\xfco\x96f\xad\ap\x89:k\x16?\xe8\x92\xc5\x1c5k\xe9\x15J\r[wx6E\x81\xe2\xa6R\x85\x93-\x8e\xc8\xceQ\xa63\x91\x1a'\xba@QlT\xee$\xe4\x93\xe5\x9e\n\xb9+\x19f\xa9\x13\xcd\xc9a\xccJ\x9d\xcb\xf8\xc1I\xc9\x16\xd7q'" +
-			"X\xcc\xc9_\x8f\xfaba\x8dmxU:\xd9Y#K\xe4Α]\xbc&kE\xe2\x04\xc4>\xc3\xd5p\x12r\x98s/\x9c\x05\x91\xf7M\x13w\xbb\u007f:\xd1\x19q\xefy:#O\x02\xb7\x86\xf2\xb4\xc8Y\xc2]Z^\x93\xa5\x9b\x0e\x9e\xcf\x10\u07ba\x15!\xf5\xa9\x83Dl\xe6 \xb1\x9a;H\xa6\x16\xde\xea\x11\xa33\x0f\x93\xed\xd7\x1e" +
-			"\xa64\x9e{\x98rx\xe1a\xcaߥ\x87)\x9e+\x0f\x13\xb9W\x1e&vc\x0f\x13\x9fS\x87m9E\x1e&>\x13\x0f\x13\x9ft\xe6\t\x88P:\xf7\x04\xc4(\xf5B\x9c\x11\xa5ԋіb\xea\x05i\v1\xf5\xa2\xb4u\x98za\xda:L\xbd8m\x15\xa6^\xa0\xf3\x99%6\xf5$5U\x8f\xeb\xdcr\x9dydψlQ" +
-			"m{\xb5<\xb3E\x81ǒ̽\xadq\xb1\xe8g\xea\xf2\xbcW\x14\xb3ˋ^U\xcc.)\x86\xd0\xc3\a\x99\xbe:\xc8\xf4\xd5A\xa6\xaf\x88\xfe\xbf\xdd\x06\xb3)X:l3\xf0\xe1\x83' \x03+\x0f\x93\x81A`\xe1S{\xd0\xd0\x01\xf0c\xb5\xf5\x8e\x19\xf4\x81z\xff\xeb\xe6a\xed\"~\xe1\xf0\x9c\xf0\x9f\x1d^\x10\xfe\x83\xc3g\x84" +
-			"\xbfp\xf85\xe1?:|N\xf8_\x0e_\x10~\xe9\xf0e\xd0+Q\xac^\xc4C\x87\xa7\x84G\x0e\x9f\x12\xfe\xd5\xe1۠WҸ&\x88)\xf7\xad`@\xe8C\xe0\x04!\t\xbet8\"\xfc\x95\xc3\x13\xc2\u007fr\x18\xb3\x8c\x82\xc7~*'\x13\xe0\x9f\xe2\xbcJ8&s]\x19#\x8b\x10\xef$\x1e?\xac\xe5\xa7\x10R\x91s\xbc\xf1D" +
-			"\x92p\x94\x8b-\x1eq\xa1\xbdTvR%!(\x96\b\x89\x0f\xae\xb9\tAs\xa6\xe2\f\x9f\xd5z+\x10W*\xb7>\f\xffd\xde\xc41/\x8d(6\xdf\x15ee\xde\xefK\xf2\xb7l\xa9\x91F\x10\xd2\x11Z[\xa6w\xac\xe75W\xf4ƷL\xe4\xf4R)\xfbPtc\xd2\v\xdef\xf6\xb9\x95\x85\xc9\xe8e\xc7\xf9\x03=\x8d\xd8v" +
-			"\n\xf5{멕\x9c\xe2^a\xd6Z͚\xdeb\x99K\xeb\xd0\u0a79r\tJxʪ܀ \xea`\x88;\xde\xd5\xf6~]\x8b\"\xa1gU\xe4\x1coڵ\xc4\x0e\x06\x12\xa1\xf0\xc2\xce\xf7]\xf0~\xcc\x13\x12\xe0\x05\xcf\x1e\xad\xb9G\xcdsT\x9e\x88Ɲ,\xe9\x0e\xf6\n\x1bW\xc0pe-t9\xfc&\xe7[\xea2\xea" +
-			"\x1e,\xeci\xbeo\xfd\x1d\x1d}\x87\x99\u0099\xdf$\x02oߜ\xd7*uA\xe0\xff\xa7\x1b\"\xd16\x02\xd4o|[\xb7\búU\xb8[ߏ\x1ab\"\x85!eB\xa6ЍE\xb6\xbb\xb8\xbd\xbdŤ\x1a\x85<\x83Q\x17ơR7\x00M[\xd3Wh\x06\x91Pͭ]\x8a\xbb\"\xdfCL\xfd\x91mu\x18\x94Rk\x81" +
-			"\x914\x8b\x931\\\x04\x8e#\xb6\x17I\x05O:\xb6/\x0e(<>\u0081(\xb2-\x0eʏ\x06V\x8f\xbe8\x1a]ݥ\xf4\xc8~\xc4\xe6S\xe2\xbd\xddd\x10#v\xe6\x9aƫ]\x9a\xa6\xb3:\xe2.2\xf2{\xb9\xc3e\xc3Fw8\x8at\x99\v3\f\xf0\x1c\xb9\xa9\x9d<3\x89f\xba>\xcev\xa2\x8e#f\xcf\xfe}-\x8b" +
-			"\x81\xc1rP\x943[\x9e\xa7\xac-\xac:\x8b\x1a\xbbif`Ǳ\x92\x13Rn\v\xba-\xf7Ƣ\xad\x81L\xd4I\xb1\xde\"\xac\xec\r7\xf0\xf2e\xb7\xba\xc3~\a\x195\x05\x1e}\xae\xae\xfd\xf9\x87-h\xd4\xdfP\b\xb5\x19\xfaΣB&\xfcG\xb6\xe5#\\J\xcf\xcc\xe7\x89\x1cl\fd\xd0\xf8\xed\xd9\x1eE\xcc\x185\x1c\xc4" +
-			"\xb56o\xb4\a\xa3\xdf\xe5\xcbm\xd1~\xa0]\xa8\xa2x\xa3\x14뻎\xa8(\xc3c\xc98r\xb4\x8e\xe0+8\x9d\x8dF#o\x9b\xf95\xdaUi]\xa7M\xe3\x0e\xdd*\xa2\x8d\xba\xd2\xd1I\x89\xa7\xbb\x0e\\B:\xdf^\xbb\xbf\xac\xe7\xed2\x11g\xab\xb0s\x89\xdf6\x8a\xc5Ɩ\xe5\xcfv\xcfD\xa9\x92\xdbw(~\x87\x8b4\xf4" +
-			"f\x8d\xfa\xa5\xeelle\x82u\x86T\x02'\xeb6=\x1e#O7m$\r=\xce\xe2l\xb8\xac\xfb\xff\xf6à\xfdfX\x85n{`)s\xbc\xe1\\\x14\xcd\x17\x8f\xabmKo\xe9\x14`\f\x03|\fV\x94\x8c6g\x94\xa6\xbe\r\xb7\x9c5\xf7\xb1\xaf@6ƃ\x9bN\xe9\xa9]\x8fQ\x17\an\xd4-7\x8c\x94qoa\xda" +
-			"6\x1b\xae\xf0SP\xa6)P84\xc0\x95\x92\x85\xacts\xc9x|\xa3v.r|QK\xdaI\a\xac\x03\xd2\f|\xc6\x1d_;4\x0e\x9e\x15\xcbQ/\xcfm\x92\xd4ڊl\x92\xef\xd2!-ǘx\x8c\xedB\x8c\x83\xa6B\x9f9\xbfm&*^\xe6\xf8\xd5\xf5|\"\xaee\x86\xe5\xa9\xc6t\b\x1e\xa1װ\xf1-\xb7\xe5\xb2l" +
-			"\xe2k\x19\xaf\xd0\x1b\xdd\u007f\xceN\xfd\xe4\xb9柛ޕ\xf5\xa1\x81#\xba\x87{\xa6\xed\\\x97Έ\xb3ҙ\xc1\x12\xc0^\x14/\xba\x82\xbe\xee]\t\xe0\xed\x16\xfcl\xf3p\x16\xd0\xcf#\r\xf8\u0082\xfbJ\x1b\x9a֫\xe1&\xab\xf6\xd6j3\xef\x97hG\xf8w\x11}V\xbd-\xf5\x14y\f\xe9H\x11\xa8;\r\x81\xce\x14k/" +
-			"\xe7\xc5\xc6d7(\xff\x12r|\x8c\xc7>\x0fb\xda1!\xfd\xa5X\xad\xfaD\xeb\xe3˿R#V\x96\xf9\xde\xde@!\xe0\x19Yٻct\x8c\x9d\xfdO\x03O\xdeo\x1a\xcdA\x81\xee\xeaoi{\xdaї\xb7w\xec\xf9\aF˧\x9dn\xf7ASJKb\xb1\xf2\xda\x1a\x96$\xd7^\x03\xe51\xa0\x8d\xfe4jn\b\xdbm" +
-			"\xe0\xd4\xc8\xc1\x1d\xee\x19\xb9k\x04\xa8\xfc\xff\x00\x00\x00\xff\xff\xe3n\xee)\x91\x13\x00\x00",
+		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\x94Wmsܶ\x11\xfe~\xbfbͦ\xba;\x1fM\xea$ٖ\xeerim'Ӥyq\xdax2ӑ\xd5\x04$\x97GH<\x80\x05@\x9fYK\xfd\xed\x9d\x05_\x00\x9en<\x93OĳX\xec>\xbbX\x00\xcb\xf8\xe9\xad.\xb90\x90(\xb9רV`T\x8dO\xe3\xc90q\xfb\x9f" +
+			"\x1aU3\xc8'\xf1\xd3\t<\x85\xdb\x7f\x90\x14\xbe\x95\xe6\x0e\x1b\r?\x97\xf5\x96\v\x9ax#\xabF\xf1ma\xe0\xecty\x1a\xc2\xdfe!\xe0\x9f\xa8\xf9\x96f\xbf\xaeY\t%OQh̠\x16\x19*0\x05\u008f߽\x03\xa9\xe0o?\xff\x00\xbf\xa2\xd2\\\n8\xeb\xf5t4\x01\xeb\xf35\xb3\x8b*)\xec\x9a\xca\xfa\x84\xa4\x81w" +
+			"\xff\xadU\x03\xaf\x99\x82\x7fɴ`͊\xb4\vc\xaaU\x1co\xb9)\xea$J\xe5.6\xa4\x964qђ\ueb3eU|\xcb\x05+\x81g\xc8 i\x17\xbf\xe6B4\xf0+\xbc\n{;\xfb\xfd>\x92\x15\x8a[mm\xe9T\xf1\xca\xe8\x18?\xa00:\xbe\xc3&\x91Le\xbf\xe9B*\x93\xd6F\xc7\x13p\xd9z+\x10\xf4\x8e\x95" +
+			"%\xa4\x05\x13[\x04\xaeW \xe4\x1el\xf2\x98B\xa8\x98\xa6\xe0\x92\x06dr\x8b\xa9\x81Ovn\x05\xd3(\x8a\xa6\xf0@V~\xb4iM\x10j\x8dy]\x86\xb0/P@#k\xd83a\xc0Hk\x05\xb4\xdc!HS\xa0\x82\x8c\x19F\xf2F\xd6\n\n&\xb2\x12U\xcbk\x96\xd7\"5\\\x8aY\xbb\x93s\xf84\x99@\xb7\xadQ\x97!" +
+			"\xd8\xc0\xa7\t\x00\xc0\x87vOV\x10\x9cF\x97A8\xb1B]a\xcaY\xf9\xbd\xa5\xd9\xea\x01\\\xae HXz\xa7+\x96b\x10vҫ\x15\x04\x86%\x03^\x9e\xae Phj%\x9c\xec\xfc\x88\xec\xc5\n\x02]\xf0\xdc8\xd1\xcb\x15\x04\xa9Q\xa5\x93\x90OVz*\xe4\xaeb\xb5v\x04\xce\xc8a\xca*]\xca\xf4\xceI\xc9\x16\xeat" +
+			"\x10\x9c\x9f\x91\xbf\x11\xf5\xf3skl\x8bu\xe5d\x17\x9d,\x93{G\xf6\xfc9Y\x13\x99\x13\x10\xfbB\xee<c\xe4\xb0D/\x9cs\"\xef\x9b&\xee\xf6\xfc\f\xa2\v\xe2>\xf2tA\x9e\xb8Ш<-r\x96\xa1K\xcbs\xb2\xb4\x1e\xe0\x8b\xe5\n\x82\x8d\xdb\x11R?u\x90\x88-\x1d$Vg\x0e\x92\xa9so\xf7\x88х\x87\xc9\xf6" +
+			"s\x0fS\x1a_x\x98r\xf8\xd2Ô\xbfK\x0fS<W\x1e&rO=L\xec\x16\x1e&>\xcf\x1c\xb6\xe5\x14y\x98\xf8\xc4\x1e&>\xf9\xd2\x13\x10\xa1\xfc\xcc\x13\x10\xa3\xdc\vqI\x94r/F[\x8a\xb9\x17\xa4-\xc4܋\xd2\xd6a\xee\x85i\xeb0\xf7\xe2\xb4U\x98{\x81\x9e--\xb1SO\xd2R\xf5\xb8\x9eY\xaeK\x8f\xec\x05" +
+			"\x91\x15\xf5nT\xcbK[\x14:U\xb2\xf4\x8e\xc6\xcb\xf3q\xa6._\x8c\x8aby\xf9rT\x15\xcbK\x8a!\xf4\xf0A\xa6\xaf\x0e2}u\x90\xe9+\xa2\xff\xbb;`6\x05\xd7\x0e\xdb\f\xbc\x7f\xef\t\xc8\xc0\x8d\x87\xc9\xc04\xb0\xf0\xa1\xbfh\xe8\x02\xf8\xa9\xdey\xd7L\xf0{\xb0\x82\xe0\x7fú`I\xf8\x89\xc3g\x84\xff\xea\xf09\xe1" +
+			"?9|A\xf8\v\x87\x9f\x13\xfe\xb3\xc3/\b\xff\xdbᗄO\x1c\xbe\fF%\x1a\\\x11\x9e9|Jx\xee\xf03¿9\xbc\tF%\x1d\xac\t\xaf\xc0\t\xa6\x84\xde\aN\x10\x92\xe0K\x87#\xc2_9\x1c\x13\xfe\x8b\xc3\xefߓ\xe0~\x9c\xca8\x06\xfc\x98\x96u\x86z\x05Im\x8c\x14!\xa4\x05\xa6w\x89\xfc\x18B\xceK\f" +
+			"\xa1\xe0Y\x86\"\x04\xbec[\f\xed\xa3\xb2\x97*\vA\xb1\x8c\xcb\x10\x14j4!hd*-B\xd0u\xb2\xe3&\x84Z\x95և\xc1\x8f\xe6U\x9abe\xb8\xd8~'\xaaڼk*\xf2w\xddS#\x8d \x84\xa0\xb7LcQ\xef\x12T4\xc2\x1d\xe3%\rje?\x8a^L\x1ad\xcc\xd8\xefN\nS\xd0`\x8fxG_\xc3w" +
+			"\x83B;\xee=\xf5\x92g\xa5L\x99\xb5ֲ\xa6Q*Ki\x1d\x1a,\x83\x1b\x97\xa0\fsV\x97\x068Q\aC\xdcAH\xfb\xbe&\\d\xf4\xadE\x89ZC\"k\x91A\xc6\x15\xa6\xa6l\x86\xe0\xfd\x98c\x120\x85\xecޚ\xbb\xd7Xbjb\u07b9\x93\x15\xbd\xc1^a\xe7\xbc4\xa8\xac\x85!\x87ߔ\xb8\xa3.\xa3\xed\xc1\xc2" +
+			"\x91\xe6\xbb\xde\xdf\xd1\xd97R\x18\x14曌\x1b\x96\x94ت\xb4\x051\x01xX\x13\x89\xbe\x11\xa0~\xe3۶E\x98\xb5\xad\xc2\xdb\xe4v\xde\x11\xe39\xcc(\x132\x87a.\xb2\xdd\xc5f\xb3\x81@\x1b\xc5\xc56\x98\x0fa\x1c*\r\x13е5c\x85n\xf2a\xddq\xeb\xb7\xe2\xad(\x1bH\x99¶\xd5aPI\xadyRb\xb7" +
+			"9\x05Ӑ \x8a\xb6\x17\xc99f\x03\xdb'\a\x14\xee\xef\xe1@\x14\x11\x13\x92\x1f\r\xac\x9d}r4\xba\xb6K\x19\x91\xfd\xc0\x14Hŷ]\x06a\xe3\x99kG\xaaߚ\xae\xb3:\xe2.2\xf2\a\xb9G\xf5\x86i\x9c\xcd#]\x95\xdc\xcc\x02\b\xe6\xeb\xd6\xc9#\x93\xb0\x19\xb6of;Q\xc71\x8e\xdb\xef\xd7RL\r\xe4\\Q\xce" +
+			"ly>c}a\xb5Y\xd4`\nf`\x8f\x90\xf1\x8c\x94\xfb\x82\xee˽\xb3hk\xa0\xe0mR\xac\xb7\xc80\xb5E\x03''\xc3\xee\xce\xc6\x1dd\xd4\x15x\xf4\xb9\xba\xf6\xd7\x1f\xb6\xa0\xd1\xf8@E\x06\xb5\x99\xf9\xce#!3\xfc\x89\xedp\x0e\xf7\xf7\x9e\x99\xcf\x1398\x18pr\xd2\xf9\x1dٞG\xcc\x185\x9b\xa6\xad6v\xda\xd3" +
+			"\xf9\x1f\xf2\xe5\x8e\xe88\xd0!T.^)\xc5Ʈ#*\xca\xf0X2\x8e\\\xads\xf8\n\x9e-\xe7\xf3\xb9w\xcc\xfc\x1a\x1d\xaa\xb4\xadӮq\x87a\x17\x9b\n\xdbJ\xbfæR\xa8u\xe0\x122\xf8\xf6\xda\xfd\xebvݾ\xe0iq\x13\x0e.ӂ)\x96\x1a[\x96\xbf\xd83\x13\xe5J\xee\xde\x14L\xbd\x91\x19μU\xf3q\xa9" +
+			";\x1b;\x99\xf1\x1c6\xe0\x1e>p\x87~\x03\x9f\x1e\xd6}$\x1d=di1\xbbn\xfb\xff\xfeǠ\xffg\xb8\t\xdd\xf1\xe0\"Ï\xa1\xf7\xd3\xd2\xfd\xf1\xb8ڶ\xf4\xae\x9d\x02,`\xfa=6\xd3\x1bJF'\xb6i\x1a\xdb\x00\x18s_l`lc1]\x0fJ\x0f\xfd~̇8\xe2\x18vh\x18)s\rF\xf1\xed\x16\x15f" +
+			" \xf3\x1c(\x1c\x9a@\xa5\xa4\x90\xb5\xee\x1e\x19\x8foԯ=9\x81'\xad\xa4_t\xc0: \xcd\xc0g<\xf0\xb5S\x8b\xe0Q\xb1\x1c\xf5\xf2\xd8&I\xad\xad\xc8&\xf9m>\xa3\xedX\x10\x8f\x85݈E\xd0U\xe8#\xe7\x9bn\xa1ªd)>^\x18BP4\x15\xaa\x05]\x82G\xe8ul|\xcb}\xb9\\w\xf1\xf5\x8co`" +
+			"c\xdf?g\xa7\xfdb\xa9\xf1sˇ\xb2>4pD\xf7\xf0\xcc\xf4\x9d\xeb\xb53\xe2\xac\ff\xe2\x98zQH\x99\x80\x04\xbd\x12`\x1a\x82_l\x1e.\x02\x90\xaa\a_Xp[kC\xcbF5\xdceվZ}\xe6\xfd\x12\x1d\b\xff!\xa2\x8f\xaa\xb7\xa7\x9eK\x053\xbaR8l\xe04\x04\xbaS\xac\xbd\x12\xc5\xd6\x14k\xe0\xf0%" +
+			"\x94k\xe0\x8b\x85σ\x98\x0eLH\xff\x9a\xdf܌\x89\xb6ח\xff\xa4F\xac\xaa\xcaƾ@!0\xb5\xad\xed\xdb1?\xc6n\xe8%\x1e&\x93Ë\xe2\x0e\x9b\xf6_\xda\xdevu\xd5\r\xdakϿ0z>\xfdr{\x0e\xbaR\xba&\x167^[òl\xe55P\x1e\x03:\xe8\x0f\xf3\ue170\xddF\xc1u\xe4\xe0\x9e\x8bL" +
+			"\xee;\xc1|=\xf9\xff\x00\xe3n\xee)\x91\x13\x00\x00",
 	},
 
 	"/static/main.css": {
 		local: "resources/static/main.css",
-		size:  224,
-		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xffl\xce\xc1\x0e\x820\f\x06\xe0\xfb\x9e\xa2r\xc7輍W\xf1\xd2\xc1\x18\x8b\xd0\xceZ\x8d\xc4\xf0\xee\x12\x90\x84\x83\u05ef\xfd\xff\xd6s3\xc2\xc7\x00dl\x9aD\xb1T\xce\x0e\xec)\xbf\xab\x1dzV\xe5a\xf3ɘ#\xe1ˣ,\xc9\x01%&\xfa\xb3\x13\xfb1w\xa9f*\x89)8\x1fZ\x96\xb0D" +
-			"f\xd3@꠸ڳ\xb5E\xb5X\xcf\xe2@\x05\xe9\x91Q\xe61\x1cҐY\x14I\xd7\xc2\xfb3շ\xfd\xcd>\xb4s\xc9e}\xf6g\x92b\xb7\xe1d\xbe\x01\x00\x00\xff\xff 9Z\xfa\xe0\x00\x00\x00",
+		size:  1561,
+		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\x94TMs\x9b0\x10\xbd\xe7Wl\x9d\xc9\xcd\xf2`7m\x12\xe5\x98s\xfa\vzY\xa4\x15h,\xb4\xaa$\x8c\xddL\xff{\a0)\xf1Gf:\x1cl\x96}o\xdf{\v*Y\x1f\xe0\xed\x06 \xa0\xd6\xd6W\"s\x90\xb0)\xc2\xfeyV,9gn\xa6\xfa\x9f\x9b\x9b\x95\xc7]\x89q@6\x18" +
+			"+\xeb/\xf4T\xee\x10j\xab\xd8\vϞdI\x86#\r\x10\xc5>\x93\xcf\x12\x16?7\xeb\xcdf\xf1<\xd4\x1cG\t9\xa2O\x01#\xf9\f_l\x138f\xf4y$\xfc\xd5Z\xb5\x9d\xcftd\xb2\x84\xaf\xa3\xd8c-ڪ\x9e\x8a=H\xb17Ϊ<\xc7\xcdL\xf6-\xa9\xad*JcG\xe0d\xb3e/!\x92\xc3lw\xf4\xb1" +
+			"g\xa5#\a͝\x17\r\xf9\xf6H\xba\x175\x1d\xc7\x16\xc7\xe8xG\xd18\xee\xc4A\x02\xb6\x99\x8f,\x84QՂC?\"}\x1e\xdfI\xefJդ\xb6%\xef\xcf\x03X\x17\x97\x12XOL\xb8jlJ\xfd\x1e\x03V\xd3\x02\x86\xb0oK,\x8a\xa2\x18\xe7ik\x8c\xd8Y\xea.\xeazg\xcbX:\x1a\x9a\x87\xb6\xce\xea\\\xf7\x8f\x8b" +
+			"\xbb^\x82a\x9f\x85\xc1ƺ\x83\x84W\xf2\x8e\x97\xf0\xca\x1e\x15/\xe1\x85}b\x87i\t\x8b\x17n\xa3\xa5\b?\xa8[,\xa1a\xcf)\xa0\xa2w\x86d\x7f\x93\x84\xf5f\xf4Ur\xd4\x14\x85b\xe70$\x920\xfd;\x11$\x92\xd5$\xca\xc3\xf0;\xa8\x1b\x9e\t\x87\an\xb3\x04c\xf7\xa4?Ō%\xdf6%Ź\xbd{jN\xcd" +
+			"\xe7z\xfe\xddH\xb8\x0f{x<\xeaE\xb5\xad\"\xb7^\x8b)h\xf3\xad\xbf\xfe\x99\x91\xb0\x0e{H쬆[\xad\xf5\x19\xbb\xfe\xc8^L\xdc;\x8a\xd9*t\x02\x9d\xad\xbc\x84\xcc\xe1\x14{\xcd\xc4\xfan\xf6\x9d\xdd>==\xf5\xb7\x99\xf6y\xe2\x1a^\x9c\xbe\xd8\xd56\x93\x18V\"\xc1s\x17\xf1ʐ\x1e\ro\xa7\x88\x10I\x8c\x18\x80" +
+			"\x8e\xa3\x1en$\x94\x91p+\xfa\xc2e\xb2\xba\xf5\xdb3\xe3\xb3X'\xe5\x0f\x0f\x0f\xd7R.̽1\x97\xd959\xca\xe3[q\ti\x88Lq\x19i}\xa2\x98\xaf!\xe9\xbb1t\xc5\x115!\x1f\xae\x8e\xc4\xfe:\x05jr\xf06\xedE\x93\xe2\x88\xe3qԟ\xa1\xd7l\xeb\xf2Q\x9d\x89\xb7>\xfd7\x13*\xb3)\a3\x7f\a\x00" +
+			"O\xe8T\xb2\x19\x06\x00\x00",
 	},
 
 	"/static/main.js": {
 		local: "resources/static/main.js",
-		size:  1064,
-		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\xa4RMo\xdc \x10\xbd\xe7W\x8c\xd8\xd5.V\xb2ުROY\xef\xa5\xed\xa9\xd7ޢ\x1c0\fk\x14\f\x16`o\xad*\xff\xbd\xe0\x8fl\x9a\xd6+E\xb1%\x9b\x19\x86\xf7\xe6\xcdcM\x85\xe5m\x8d&d\xb9C&z*[Ã\xb2\x86f\xf0\xfb\xe6\x06`\xbf\x87\xdd\xdbwL\xffd\xa5F" +
-			"\xb0\x12\xb85!\"\xf8\xa5\xea\x98\uf603*\xd4\x1a\nXS\xb2*\xad\xe8\xc1\xb0\x8edy\xca\xd2\xec>\xd6(\t4\xf4\rFı\xb4(\x80\xf8\xe0\x949\x11\xd8l\x86d\x1eÚf\xa97\x88\xcf\x1b\xac\x12\xa5uHa{\xf0\r3ǯS_\x87\xfd\x10nL\xe9\x9b\xfb\xed\x1dl\x1f\x0e\f\x94(\x88\xaf\xecyW)\x81\xbb" +
-			"`9\x81ʡ,Ȋ\x1cS\xea\xb0g\xc7\xc7-\f\x9d='\t\x89\xeb\xef\x03Yε\xe2O/#\x03\x8a]\x9a\xe4\xd4\xdc\x10\xe4\x8d\x1b\xfe\xdfP\xb2V\x87Q\xe9?\x8d\a{:i\xa4\x9f\xbf|\x9a\xf6\xd3,\xd64T\xca\xc7M\xfc\x15ύ\xe3H\xdcd&H0\xafJ\x069d\x02x\x06\xd4\x1e\x17\nG\x94\xa90\xe9\x8b" +
-			"\xeb%\xafg\xb7\u007f`_Z\xe6\x04D\x16\x17x\xbbh\xf7h\xf8\xc3|\xaf\xee`T\xbaC\xa1\x02IQ\x8d\u07b3\x13\x92\xc7<\xba\xf5\x9d\xf1\xear\xe7<j\xe4\xc1\xba\x8b\xbf/\x99\xbcTFP\U00084f70g\x93\x80\x98\x0e\xb7\xbeR2\xdcb\f/&\xbc\x1e\x0fY\r\xb4\xb3S\xb3\xe6\xa4\xf7\x1d\xf8\xcd\x15\xfc\xe4\xaf\xc2\xf3G)" +
-			"\xc4\x15\n\xa1\xa4\xfc(\xbe\xbf\x82\xefY\x87\xffßX\xd2\xe7O\x00\x00\x00\xff\xff\xc2u\xdei(\x04\x00\x00",
+		size:  4154,
+		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\xa4Wms۸\x11\xfe\xae_\xb1\x87sc\xb0\xa6I\xfb\xae\x9d\xb6\x96\xe8N'\xbd\x0f\xd7˴3\x89\xf3IV:\x10\xb9\x14QS\x00\r\x80R<\x89\xfe{\a/\xa4Hْs\x93\xc9L,\x02\xbb\xcf>\xfb\x82\xc5\xe2\x8c\x162o\xd7(L\x94(d\xc5\x13-[\x91\x1b.\x05\x8d\xe0\xcbd" +
+			"\x02\x90\xa6py\xf8\xcf/߱e\x8d Kȥ0(\x8c>&=\x01\xd80\x05\x95Yא\xc1\x19%?.e\xf1\x04\x82mH\x94\xd8U\x1aM'\x00\xbc\x04j\x9e\x1a\x94e\x10\xcd2 \xda(.V\x04\u07bcq\x8b\x89Q|M#\xcb\r\x00\x0e\xb1\x96XJ\x85\x14\xceg\xbaa\xe2\xf6m\xe05K\xdd\xe7\x1b\xb1\xd4\xcd\xf4" +
+			"<\x86\xf3\xf9\x8c\x01/2\xa2+\xb9\xbd\xacx\x81\x97F\xe6\x04*\x85eF~$\xb7vi\x96\xb2\xdb\xc598f\xbb\xc9\xc4\xdb\x1a+DI^\xf3\xfc\xa1\x0f\x19P\xdc\xd8H\x06r\xee#i\x94\xfb\xfbO,Y[\x1b\xef\xe93\xe2F\xaeV5ҟ\xfe|\x15\xf6m,Ψ\xa9\xb8\x8e\x12\x83\x9f\r\x8d|8\xacm\xd2\x19\x00\x18" +
+			"\x898wH\x00\xd8\x01\xd6\x1a\x8f\bz\x94 h\xfd\x8b\xa6\x93Wr\xcdM\x8d\xa0\xdb\xd5\n\xb5u\xf5T\xae\xfbp4l\x85\x1f߿\xa3\xc6*w\xa4\x15\x9aV\t )\x81\vp;\x89njn(II\x94\xacYCQ\xe4\xb2\xc0\x8f\xef\x7f}+\u05cd\x14\xae6\xff'\xb9p\x12}:\xd2\x14>Tr\xaba\x89\xb5\xdc\xc2\x19" +
+			"\x17Mk\xc0T\xe8AuG\x16\v(\xa5\x82m\xc5\f<\xb6\xa8\x9e\xa8\x17\x8d\x02\x13\x1d{0&\n\xc8Y]k\xc8+)5\u0096\x9b\xca\xe1I\x81\xd0\xf0\xfc\x01\x8bd\xe8]\xc0\x0fp\xb1\a\x8f\x83v筭\xfb\xb35\x8a\xd6\x15\xfe\xf9\xac\xad!\xaf\x99\xd6\x19)\x94l\n\xb9\x15\x97v\x97\xdc\xceҶ\xbe=\x8f\x12.4*" +
+			"\xf3\x8fҠ\xea\x88N{$\xc3רbP\xf8آ61\xb0\xdc\xf0\rB\x06\x97\xd7Ӊ\x93\xea\xd9\xe5\xb5\xd4H\a\x95b\xcd$6\xf14Jpݘ\xa7\xae\x14a\f\xe3kb\f\xa6\xb1\xc6\xdcP\xb1\x87s~q\x83k\rY\xc0\xce+^\x17\n\xc5\v\xb8\x94\n\xf8C\x90Oj\x14+SEpq\xb8p ѡ\x84E" +
+			"\x85k\xb9\xc1\xb76x\x94xd\x12%\xf8H\xfd\xef(aEq\xb0;r\xc6\a3a\xc6(JXkd.\xd7M\x8d\x06I\fD\x96\xa5\x15\x1f\nJA\x89\xfbE\xe2} \x06\x01\xcdkdꎯQ\xb6\x86\xba\xc4\xf4~\xbb/\xc8@\xa3\xe9\x04^B\xf0Q|\x84l\\\x97\xd3~\xdb6\x81\x90\xeb\xa1\x16t\x05\x90\xb0\xa5T" +
+			"\x86\x0e4v#\xdd\x1f\x1e\xc7j\xa1&\xa6#${\x06^\x02\b6lz\x93\x15\x9a\x7f}\xf8Ͽ)I\xff\x9b\x86\xb2'1|y\xbc\x81\xc7\xdd0:\xfe\xe4\xbdjԋ%\xa5T\xbf\xb0\xbc\xa2\a\xfacu\xd7*g5\xbf%Q\u009a\x06EA\xed\x02s\xdf.\x97\xb6k\x93\xf8\xa0لN\xd7}x\xcd;I]\xa9\x8e\xc8" +
+			"\xecF_\xee\x0e\xf2\xf4\xba\xb2<`\xe3j\xddvڱO\xbb\xc9s\xc0]\f\xd7}G߽P_\x0f\xf8d[\xc0\xa8\xc2FWHȣ7\xca5%7\x1b\xae\xf9\xb2F\x12\r\x89\x8d\xb3\xd8Q\xd1[n\xf2* &ۊ\xe7\x03or\xa6\x11\xfetuc[\x9f\xe5\xd0c\x85\xb3\x1e\xce\xee\x05\\\x0f\xdc\\*d\x0f\xd3!\xc2" +
+			"\xcf\x7fu\bmsD\xff\xf2\x15\xfd\xbf9uÖ\xc3\xc5\xeb\x9f\xdd*\n\x83jT\xd0\x01t\x06W\x87\xe7\xe1X\x15\xfbnL\x0f;\u0530o\xf8\xebu\xc0\xf2Y\xc5>\xa7\xfd\xd3_<C\x9d\xb3\x06\xbfQ\xb1\xf0\x13\xc0\xcd+Y;55\xf45\x94\xa6\xf0\x1bb㮧R\xe6\xad\x06)\xba+p[\xa1\x006\xb8\xab\x81kp" +
+			"\x83\n\x16\x93}\t\xdb\xfa[\xcbVc\xa8@\xc2N\x95\xe1)V\xfb(\x8f\x06\x96\xfd\xee0,/\x1d\x83e\xdd*\x12{\xb9\xd1\x05\x7fW!hd*\xaf`)?\xc3J\xa2\x06#\x9d\xd7\xf6\xb8\x87[y\x02\xfb\xbb\xd8\xcehN\x81DCo\xba+\x7f4\x82\x04\x06\x1bV\xd3(̕\xce\xf8\xb3\x8e\xd6\xe9m\xb9(\xe46\xa9e\xce" +
+			"\xecnb;\x0fd\a\x9dg:\xe9\x9dLS\xf8U8\xb6Xp#U܍&L\xe1`<\xe1B\xf3\x02a>\xaf\xb9xЋE\x18\x97e\x83\xe2\x1d\x17\x0f\x90Az?\xbf\x9f\xd3\xf9\xa7\xfb\xf9\xfd\xe2\xeb\xbdX\xfc1:K\x9d\x85\xa1\xdfv\x9c\xbc\xb4\x86\x0e\\\xb7\xd9`\n\xd9p$\xe9\xd6lw\xef~ϯ\x16\xfbIc\xcd" +
+			"l\xeb\xc8z\x12\t~Ɯv\xa26f-&\xba\xe69ҫ\xb8\x87K\xfc\xe1\xe7R|0L\x99\xae\x04B\xbc=\xe6\xdf\xfd\xdf\xf9\xf5\"\xc4\x1cn\x80\x90Ӂ?\xa4<\xf6vD\xdc?\x01 \x83\xdf\xcb5Q\xd8\xd4,Gڹ\x1c\x03\x99\xcf\xfb!u0\x851;\x9c\x1d\xb3p\f~?\xd6\xff\x90~\xa2\xf7\v\x9bȯQ" +
+			"\x9a\x18\x9b>\x878\xe8\xe8\xc1\x89\x8b\f\xc8bA\xf6s:\x1c\u0604\xac\x17\xf5\xac\xa6c\xa11\a\xc8^\xd8\xf9E\x14=\xca`\xf0:\xf5\"\xe8\xde\x04\xbf\xe1\xd3R2U\x80\xae\xa42y{\xf4\x01\xe8\x9f\x05\xf3\xee\xa5\x19\xc30}\xf6k\x8dZ\xb3\x15\x92ų\x99\x80z\xa2R\xed_|\xfdJ\xb2\xe4\xa2\x18^\xa2\x84\xd5\xe6BW" +
+			"\xbc4\x17xdj\xb3\xa5\xe3\xab&\xbcݞ\xb5\xa5o\xc3oN\xe0\xdb.\xc9q\xfb\xbd&\x8a\x13&\n^\x96ߋ\xafO\xe0k\xb6\xc1\x97\xf0\x83\x15\xfb\xdf\xff\a\x00\xc0'\x8a\xd7:\x10\x00\x00",
 	},
 
 	"/templates/_base.html": {
 		local:      "resources/templates/_base.html",
 		size:       91,
-		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\xb2Qt\xf1w\x0e\x89\fpU\xc8(\xc9ͱ\xe3\xb2\x01Q\n9\x89y\xe9\xb6J\xa9yJv\\\\\n\n\xd5\xd5%\xa9\xb9\x059\x89%\xa9\nJ\x19\xa9\x89)J\nz\xb5\xb5h\xe2I\xf9)\x95\x10q.\x1b}\x88Q\x80\x00\x00\x00\xff\xffmS\aw[\x00\x00\x00",
+		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\xb2Qt\xf1w\x0e\x89\fpU\xc8(\xc9ͱ\xe3\xb2\x01Q\n9\x89y\xe9\xb6J\xa9yJv\\\\\n\n\xd5\xd5%\xa9\xb9\x059\x89%\xa9\nJ\x19\xa9\x89)J\nz\xb5\xb5h\xe2I\xf9)\x95\x10q.\x1b}\x88Q\x80\x01\x00mS\aw[\x00\x00\x00",
 	},
 
 	"/templates/_body.html": {
 		local: "resources/templates/_body.html",
-		size:  1641,
-		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\xbcU\xcdn\xdc \x10>\xd7O\x81\xe8\xd9Fin\x95m\xa9R\x1f\xa0\x87ޫ\xb1amR\f\x0e\x8c\xab]\xad\xf2\xee\x1d\xf0O\xd6n7驑\"\x0f\xc3\xcc7\x1f3\x1f\xec\xf5*\xd5I[\xc5x\xe3䅿\xbcdY\x19\xad:c\xac\x94\xfa\x17k\r\x84P\xf1\xd6Y\x04\x8a\xf3\xbc\xceh" +
-			"\x8b6-l\x9bd6\xe0\xd9\xfc\xc9\t\x10&\x83\xbcNqw`\U00093674\xdcb\xf6Q\vP\xaf@\xa6\x82l\xfb+\x9b\t\xd1Y\x86\x97QU|^\xf0C\x1a\xba\xae3\x8a\xb5\xce\x18\x18\x83\x92\x9cI@Xܑ\xc2\xec_\xdd\xe0;\x85\x15\xff8gs\x06^C\xae\xce#X\xa9d\xc5O`bl\xf2F\xf6ޙ\xadԎ" +
-			"\x1a\x91\v\x94\xb4\x92\t>w\xd6\\x\xfd}\xa6C\x19\xba\x03\xd4Ζ\"ƽ\x91\xaa\xa9N\x9e\xe0\xffWh)\xe6V\xee|p\xe8k\xe3\xa9%\x9c\xf5^\x9d*.\b\xe6\x16\xbd3\x97\xb1\x8f%\xd8f\xe5\xbd\x1b\xd6\xce\xf5ZJe+\x8e~R\x1b\x81R\xc0\xcd\xfc\x05\t\xe0 \a-\xb7N\x1f\xb8\xacCܦ\xbcW\xc9dn" +
-			"\xe2W]\xd2\xe780\xa3k:\xe6r\xa2\x1fb\x84N\x05^\u007f1\x86}\x8bf$X\n\nz3K*\xa3\x90dV\u007f\x9d\x8d\xfb\xb9\xa5\x98L\x9d} \xe3\xe4\xfcp8Rr-\xb6\xd7]\x8f\x9c\x91\xd6H\xb0A\x81o{jd\x1bՓJ.\xae\x03\xaf\x9b\x1b\x14\xc1\xf2λi\xe4\xa9`\xda\xd7v\x9cp\xb9:\xa8\xceT" +
-			"\xc0\xc2@\xf63ߥ-*?\xa2\xef\xe7\x93\\\xbb\xcb\x18\xa6fиA5h\x19\xfd\xbf\xbe\x06\xef\xcae;\xe7\x1b\x82\xf9\x8bLE$}GG7\x8bRPo\x97\xb7\xebzE5\x8c\x06\x90\x9e\xbd8\xf3|nmଠ\a\xf0\x18\x11\xfb\xa1,Λ\xd9\x06\x1a\xad\xd0z=\"\v\xbe\xadx\x8f8\x86\xcfB\xc0\x13\x9c\x8b\xce" +
-			"9\xba\xf40\xeaP\xb4nH>\xd2B\x13\xc4\xd3\xf3\xa4\xfcE<\x14\x0f\x0fŧeU\f\xda\x16O!\x1d3\x01\xd6\xf7\xb0\a8\xb7\xd2\x16\x8ds\x18\xd0\xc3\x18\x17\x11\u007fs\x88\xc7\xe21\xe2\x86W\xd7\xfb\xe8QPHOS\xbb\xf2\xe9\x1d\xfeT\x97\xf0\xafY\x03\xfcQ!\xa3Q\xa5_\x91\xeczUVR\xe3~\a\x00\x00\xff\xffC" +
-			"2\xf9\xd6i\x06\x00\x00",
+		size:  2376,
+		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\xbcVMo\xe46\f=\xaf\x7f\x85\xa0\x9em!\xdd[a\x1b\b\x02\xf4\xb4h\x17\xbb)\xf6\xb8\xa0-\x8e\xad\x8c,9\x12=\xcd\xc0\xf0\x7f/\xe4\xaf\xc6\xced2\xa7\xcd%\x12M>=\xf2Q\xd4\xf4\xbdă2\xc8xa\xe5\x99\x0fC\x14\xa5a\x95G\x8c\xa5R\x9dX\xa9\xc1\xfb\x8c\x97\xd6\x10(\x83" +
+			"\x8e\xe7Q\xc4\x18c\xa9\x81\xf5\xa3\x81S\x01\x8eM\xffb\x89\a\xe84\xf1|\xf4{\a&>\xe8N\xc9\xd5g\xeb5\x03\xd5\br<\x90\xad\x7fi\xd1\x11Y\xc3\xe8\xdcbƧ\r߅\x91\xad*\x8d\xac\xb4ZC\xebQr&\x81`6g|\xb1/fp\x15R\xc6\x7f\x9b\xa29\x03\xa7 Ɨ\x16\x8cD\x99\xf1\x03h\x8f\xb35\xb0" +
+			"wV\xafGm\xa81\x96\xfa\x16\xccBƻ\xd8\x1a}\xe6\xf9\xe3D\xc7\xc0IU@ʚT\x04\xbf+\xa1\xaa\xb4&\x1e\xe1\x7f\x95k*\xa6Rnl\xb0\xabk\xe1\xc0H\xcej\x87\x87\x8c\v\x9eo\xd0+}n\xebp\x04[Wqm\x9b\xa5r\xb5\x92\x12M\xc6\xc9u\xb8\x12H\x05\xbc\xd2_Huڵ\x83\x92k\xa5w\\\x16\x11" +
+			"W\x95\xb7]\xd2\xe9W\xfeK_\x1a8\xed\x05\xd3*Oa\xc9\xe8\xa7h\xa1B\xcf\xf3{\xad\xd9װ\f\x04S\xa1\xd5\xf5(\x87%\x1a\x8a\xcb\x1a\xcc\x18\xfemܳ\x87i\x7f\x19\xa3\xefՁ%\xf7\xb2Qf\x18\xae\xa2C\xa9y~_\x96\xe8={\x98\xfa\xef6Z\xffbQ[{\xf4<\xff1\xafn\x8c\xb3\xeeH\x0e\x91\xe7?\xac" +
+			";\xb2G\x87\xf8^\x0eh\xe4\x86}*:\x9dG\xfb,\xff\xf1\xe8\xb6n\a뚝\xa0\xa3i^;U\xd5\xc4\x19\x94ᶌ\x9c\xb4\xadlG\x9c5H\xb5\x95\x19\xff\xfa\xf7\xf7ǽ\x96ʴ\x1d̓aj7\xce\f4\xe1\xc6{w\xf8I\xf6\x18,'\xd0\x1df\xbc\uf4c7\xef\xdf\xfe\x1c\x86kWx\x99'\xf8B<\xef\xfb" +
+			"1\x91\xe4/hp\x18.\u07b6\xcdl\xf2]\xd1(Z\xfb\xb6 \xc3\n2\xb1V\xe6\xc8\xf3/\xb6b\xb6\xa3\x8b\xd7N\x84Rl\xab\x88\xda#\v\xa5\xfcb\xab]ü\xdb\xea\xdbb^\x97\\\a؉\x952\x97\xc4~+\xec$\xfd\xa7\x9b\xc5t6\xcc^\x8f\xe0\xcaz#\xedl\xda\x11|\xf5\x18\x04\xb0\xb8r\xb6k\x99\xef\xaa\n}" +
+			"\xc8\xe6\xd3\x05\xd1G\x9dƙ\xb1\x1c3\xc9\xff\xcc7P\xf3\x10ߟ\xb8\x1d?7\xeb\xb9>v\x1fN\xc35\xf7+\xf3\xf0\xe3v\xd8\xf0|\xb5I\x85\x81\xd3\xfc4\xf7=a\xd3j d<\x8c\xb4x*\xb7\xe7,\x19\x867\x1e\xa1\x1ehh\xfa\x18\xad\xa0a\xe5K\xa7Zbޕ\x19\xaf\x89Z\xff\x87\x10\xf0\x04/Iem\xa5\x11Z" +
+			"\xe5\x93\xd26\xa3MhUx\xf1\xf4ܡ;\x8b\xbb\xe4\xee.\xf9}\xde%\x8d2ɓ\x1f\xd3\x1c\x01\xf3\xf7\xb0\x1bx)\xa5I\nkɓ\x836l\x02\xfej\x10\x9f\x93\xcf\x01\xd7\xffo\xfa\x18=4\x19\x01\xa9r\xe1S[:\xe2\xd9\xdf\x1a\xd5\xc0\x9b\x13\xa2TL?\x92\xa2\xe5&\xfc7\x00%\xb2\x83\x8fH\t\x00\x00",
 	},
 
 	"/templates/_delete.html": {
 		local: "resources/templates/_delete.html",
-		size:  880,
-		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\x84Sˮ\x9b0\x10]\xdf|\xc5ȋ\xee(\xaatw5d\x91,[\xb5R\xf3\x03\x06\x0f`\xc9x\"3\xb4E\x88\u007f\xafy5$A7\v\x84\x99ǙsΘ\xbe\xd7X\x18\x87 4Zd\x8cj\xd2ʊa8\x1c\xa46\xbf!\xb7\xaai\x121E\xa1P\x1a\x05\x18\x9d\x88\x9c\\a|" +
-			"\x1d\xcdM\x02Xe\xc6i\xfc\x9b\x88\xe8\x8b\x00O\x16\x13\xa1\x8d\xb2T\nPިȪ\f\xadE\x9du\x01\xac\xfb>\xc2}\x1bCK\xba2Z\xa3K\x04\xfb\x16Ez\x00x\x1a\x1e-pcr/\x1d\x181:\x0e\xf9\xa9`\xaf\xa4\xc2\xc0\xdf/\bSI\xd62\x93\x03\ueb81\xef\xfc!֞\xdcR\x13\x94i\xc5*\xccnj\xf3" +
-			"\x1fh\x97\xf3'656_e<\xc3l\x86T\xef\xf74ذ]l\xbcs\"=ͦ\xc2y2U\xc6\xd5\xfb\x8a\"\xe3 \xe6\x03e\x19\xe9n\xab뚞\t:j\xe1\x8fr\fL0\xef\tdÞ\\\x99\xf6\xfd\xe7\xcb\xc8b\x18d\xbc\x84\x8e2\xben\x11\xd6\x01\x1a\xb3\xb6\x8cZ\x1f\xf8m*^\x11*\x88x\xb2\xfaM\x16" +
-			"\x14$\xa9\x9c\r\x05\xaf\xe2\xdb\xe4\xe3\x12[\xafP\x8d\\Q\xf0\xe4\xe7\x8f_\x97\x8d\x96\x17[\xca\xd8Ax\xc2E,Tky\u007f_\xe9I\xb9\x1c\xed\xf3nn\xe0\x8fpʕ\xe8a~\x89t\xdd\xc8\xda\xff&\xe3Qփ\x1bw\xc7\xe5\xb0\x1a\xd5\xf7\xe8t\xf8\xab\xfe\x05\x00\x00\xff\xff|\x16\x01\xe3p\x03\x00\x00",
+		size:  941,
+		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\x84\x92Mo\x9c0\x10\x86\xcf\xe1W\x8c\xe6\xd0\x1bA\x95r\xabM\x0e\x1b\xf5ԪU\x93{e\x98a\xb1j\xec\x95\x19\xb6E\x88\xff^\xf1հɪ{\xb2\x99\x8fw\xc6\xcf\xcb0\x10W\xd63 \xb1c\xe1\xb4\td\x1c\x8ec\x92(\xb2g(\x9di[\x8ds\x14*C\x8c`Ic\x19|ec" +
+			"\x93.M\bb\n\xeb\x89\xffhL?\"\xc4\xe0X#Y\xe3\xc2\x11\xc1DkRg\nv\x8e\xa9\xe856\xfd\xd7I\xee\xcb\x14Zӵ%b\xafQbǘ'\x00\uf1a7\xabܔ\xbc\x96.\x83\x17\xf6\x82y2\x17\\+\xa9\xd9\x10\xc7Ua.):\x91\xe0A\xfa\x13k\\>p\xeb)]h\x19\x81\x8c\x98\x94l\xdb\xd8\x7f" +
+			"BWw\xfe \xb6\xe1\xf6\x93\xca\x16\x99ݐ\xfa\xe1r\r\xb1\xe2V\x8c\x17$\xf2\xc3\x02\x15\x9ef\xa8*\xab\x1f6\x15\x95\x91=\xff\xe7eE\xa0~\xff\xaeS\xfe\x14\xa0\x0f\x1d\xfc6^@\x02,>\x81j%\x06\x7ḟ\xe1\xfee\xdab\x1cU\xb6\x86\x1eUv\xda+l\x03\x88\x8b\xee\x98v\xd1a\xbe\xab\xb8\xb5P\x15\x82̨\xef" +
+			"T\x15b\x03\xa6\x14\x1b\xbc\xc6\xecu\xf2\xe3\x1a\xdb~\xa1\x86\xa5\x0e\xa4\xf1\xfb\xb7痩\x11@Y\x7f\xeadug\xa1\x8d\xe0M\xc3\x1a\xcb6V?%\xfc\x9a\"g\xe3:\xd68\f\xf7\x87\xe7\x1f\x9f\xc7q\a\xe2\x86Ņx(ħĕ\xe9\x9c\\7;?\x18_\xb2{o\xec\xab\xf8[9\xe3\x8f\x1ca90\xdf\xec\xdc\xfa\xef" +
+			"T61y\x83\xf2\xe2\xba^\xd6#\x19\x06\xf64\x8e\xc9\xdf\x01\x00,j\x04\x1d\xad\x03\x00\x00",
 	},
 
 	"/templates/_edit.html": {
 		local: "resources/templates/_edit.html",
-		size:  1518,
-		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\xa4TM\x8f\x9b0\x10=\x87_1r\xcf\x04\xf5N8t\xb7Ǫ\x91\xb2\xea\xdd\xc1\x03\xb1\xea\x0fjL\xaa\b\xf1\xdf;6&ɪI%\xb6\a\x10\x8c\xe7\xf9\xbd73\x9aq\x14\xd8H\x83\xc0:\xdeb\xcek/\xad\xe9\xd94eY\xc9\xe1\xe4\xb0ٱO\f\x04\xf7<\xf7\xb6m\x15\ue636\x82" +
-			"\xab%\xc6]\x8b\x9erjk\x1a\xe9t.P\xa1G\x06\xb5\xe2}\xbfcGo\x80\x1e\n7|P\x1e\xbaA\xa9\xdc\xc9\xf6\xe4\xe1\xd7 \xeb\x9f\xf1\xb0\xd7\f\x9c\rW\x1f\a\xef\xada\xd5k\xbc\xa5,xu\x93Q\x8c\xe3\xf6Mz\x85\xd3\xf4\xbf\xd7\xff\x90\xf8\x1b\xf6d82d\xe3\x88F\x04\xcb٭\x1c\xe4ǣ\xf1s%N\x9f\xab" +
-			"\x1b9\x94\xbd\xe6J\x85\xc8a8\xa6`Y\xcc\xc1\xb2\xa0\\B4\xd6i\xd0\xe8OV\xec\xd8\xfe\xfb\xe1\x8dU\x19\xc08\xca\x06\xb6_\x85\xf4\xd3D\xa4\xaa'$\x85Ki\xba\xc1\x83\xbft\xa4\xf1$\x85@\xc3\xc0p\x1d\x14[qap\xe6j\xa0\x1fb\xfcB\xff\a;\xb8:T\xa1\xfa7Vcߓ\xc7{\xf8\x8b\xd5Z\xfao\xf3A" +
-			"\xba\xe1\xea\x9e.\x13\U000bc536\xe3\x06\x15\xc4\xf7Rޘ\xfe +\x8f*\xab,\x9e\xd2\xf9\\椩\x1f\x8eD\xc9@R!z~\xfe{4:'5w\x97E\xf5<\x82W\xd1\x11R\x1d\xe8]\x16\xf3\xbd\xd5B\xb3b0X\xf5\xc2M\x8dj\xeew\xc2\xdf\xd9\b\xa9\xad\xb3CG.6\xf7]\xca6\xefݤ\tz6\u007fA|" +
-			"\xd0\x1b\xc07\xbd\x9bk\xaf7\xcfk\x83\"|=\x91\xff\xb86\x11\xf2\x80kng\xf2\xb1wx\xa6q\xff\xb0\x95\x84_馛Q\f\xd69Z`\x8fY\xdf\xf9z\x95M\xf3aS\x01\xbcґ \xc8\xca\xfeD\xc8\x03\xae\xe8#\raAS\x98F2}\xdf\a\xc7ѣ\xee\x14\xf7\xb4\x91B\xb7\xf3e-\xc16.\xa6\"외" +
-			"\xc1ny\xf3\x06\xceӖ\x8ey\v\xe7\x9f\x00\x00\x00\xff\xff\xba\x9a\xa83\xee\x05\x00\x00",
+		size:  1649,
+		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\xa4UOo\xa3>\x10=\x87O1r\xcf\x04\xf5\x0e\x1c~\xedoo\xab\xad\x9aj\xaf\x95\x83\ab\xd5\x7fX{H\x15Y|\xf7\x1564\xcdn\xa2(\xddK\x14\xc6\xf3f\xde\xf3\x1b\x86\x10\x04\xb6\xd2 \xb0\x9ew\x98\xf3\x86\xa45\x9e\x8dc\x96\x95\x1cv\x0eۊ\xdd1\x10\x9cxN\xb6\xeb\x14VL" +
+			"[\xc1\xd5\x12\xe3\xaeC\xaa\xd8]cM+\x9d\xce\x05*$d\xd0(\xee}Ŷd`K&\x17\xd8\xf2A\x11\xf4\x83R\xb9\x93ݎ\xe0\xd7 \x9b\xb7x\xe85\x03g\xa7\xd2ہ\xc8\x1aV?\xc6*e\xc1\xeb#\x8d\"\x84\xf5\x8b$\x85\xe3\xf8\xaf\xe5\x7fJ|\x87'ޥ\x0eY\bh\xc4$9;^Gc\r\xa1\xa1t\x13\xbb" +
+			"\xfb\xfa\xd8\x1cJ\xaf\xb9RSd3l\xe7`Y\xa4`Y\xec\xee\xeb,+[\xeb4h\xa4\x9d\x15\x15{\xfa\xb1yau\x06PJ\xd3\x0f\x04t\xe8\xb1b;)\x04\x1a\x06\x86k\xacX\xe3]\xfbJ\xf6m\x8a\xec\xb9\x1a\xb0b!\xac\x1f6\xcf\xdf\xc6\xf1\x1av\xcb=\xbe:\xdcK/\xed\t\xfc?\xee\xf1y\x8e\xcfeB\x90-\xac\xff" +
+			"\x17\x92\xc61\x04T\x1e\xc7\xf1Ju+\x0e'E\xad8l\xec\xe0\x1a\xbc\xceL\xa3\xf7\xbc\xc3\x13IVkI\xdf\xd3\xc1\a\xa9\xd9\x00\x80R\xc8\xfd\xe2n\xcf\r*\x88\xbf\x8b\xc31\xfdLV\x1eY\xd6Y<\x05(\x93\xd33'?l\xb5$\x06RT\xcc\xf3\xfd\xdf\xd3\xd9;\xa9\xb9;,\xac\xd3[\xf0A:B\xea\r\xdfcY\xa4\xba" +
+			"\xf5\xd2\xe6\x86\xd9d\xf5\x037\r\xaa4r\x00\x7fʘR;g\x87\x9e\xd5\xd9\xea\xb3K\xd9\xeaTMz\xb8\xf8\nL\xe4'\xbe\x13\xf8\xc8w\xf5\xe1\xf5\xea\xf2ݠ\x90t\xa9\ue17b\x89\x903\xbd\x92\x9d\xb3\x8e\xa7i6\xf1\xfd\xcbRf\xfc\x8dj\xfa\x84bp\x9b\xa2\x05v\xbe뉮Gٶ_\x165\x81oT$d\xdb\xde" +
+			"\xe8O\x84\x9c\xe9\x15u\xccCX\b\xb9\x9fGr\xfe\xff9\x18\x02\xa1\xee\x15'\x84\xe8v\xbelFX\xc7\xddXL\xab..\xd1c^\xfa\b\xe4\xf3\x87\"\xe6-=\x7f\x0f\x00ҁ\xd2\x06q\x06\x00\x00",
 	},
 
 	"/templates/_head.html": {
 		local: "resources/templates/_head.html",
 		size:  413,
-		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff|\x90OO3!\x10\xc6\xef\xfb)\b\xe7\x97%\xaf\xbd\x18\xb341ƃg5\xf1f(Le\"\v\xeb2m5\x84\xef.t\x9b\xb6'o3\xcf3\xbf\xf9\x97\xb3\x85-\x06`܁\xb6\xbc\x94\xae\x1bZ\xb4\xee\x18\x1bF ͌\xd3s\x02R|G[q\xcb/\x86#\x9a\x04|\xedp\xaf\xf8" +
-			"\x9bx\xbd\x17\x0fq\x9c4\xe1\xc6\x03g&\x06\x82P\xa9\xa7G\x05\xf6\x03\xae\xb8\xa0GP|\x8fp\x98\xe2LW\xa5\a\xb4䔅=\x1a\x10\xc7\xe4\x1fÀ\x84ڋd\xb4\a\xf5\xbf\xb6i}\b\xc9\xc3:\xe7\xfe\xa5\x05\xa5\xe4|@r\xac\u007f\xdemN\n\x13\xac\xdá`K\x19\xe4\x82\x1ci\x8f\xe1\x93\xcd\xe0\x15O\xf4\xe3!" +
-			"9\x80\xba\x86\x9ba\xabx;*\xddI9\xeaocC\xbf\x89\x91\x12\xcdzj\x89\x89\xa3<\vrկ\xfa\x1biR\xbah\xfd\x88\xb5*\xa5\xe5ؿ\xc6\xc8w\x99\xa8\xfe\xca\xd4Agf\x90\xcb\xe7\xbb\xd3\xd2\xddo\x00\x00\x00\xff\xff<\x87=j\x9d\x01\x00\x00",
+		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\x84\x90MO3!\x10\xc7\xef\xfb)Ȝ\x1f \x8f\xbd\x18\xb3lb\x8c\a\xcfj\xe2\xcdP\x98\xcaD`\xd7e\xdaj\xc8~wC۴\xbdy\xfb\xbf\xf0\x03fj\xf5\xb8\xa1\x8c\x02\x02Z\x0f\xcb\xd2u}SC'D\x9f\x90\xadp\xc1\xce\x05\xd9\xc0\x967\xf2\x16.E`\x9e$~mig\xe0M" +
+			"\xbe\xdeˇ1M\x96i\x1d\x11\x84\x1b3cf\x03O\x8f\x06\xfd\a^q\xd9&4\xb0#\xdcO\xe3\xccWG\xf7\xe49\x18\x8f;r(\x0f柠LL6\xca\xe2lD\xf3\x1f\x86\xae\xdd\xc3\xc4\x11\x87Z\xd5K\x13\xcbR\xeb\x9e8\b\xf5\xbc]\x9f\x12!E\xad\xaa5\x98\xfd\xb2\xf4\xfa\x88\x1c\xe8H\xf9S\xcc\x18\r\x14\xfe\x89X" +
+			"\x02\"\x83\b3n\f\xb4\xa1ʝ\xd6\xc9~;\x9f\xd5z\x1c\xb9\xf0l\xa7fܘ\xf49\xd0+\xb5R7ڕr\xc9T\xa2\xac\\)0\xfc\xf5\x8c~ׅ-\x93\xd3ɞ\x99^\x1f7ߝ>\xdd\xfd\x0e\x00<\x87=j\x9d\x01\x00\x00",
+	},
+
+	"/templates/acl.html": {
+		local: "resources/templates/acl.html",
+		size:  1171,
+		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff|SMo\xeb6\x10\xbc\xf3W,\xf6\xd4\x06\xb6\xd5\xf4\xe8RB\x82\"=\xf4\xd0\x14qnA\x10\xd0\xe2Z\"\x1eE\n\xe4ʎ!\xe8\xbf?\x90\x92\xf3\xe1û\x18\xda\xd9ݙ!=\x1cGM\a\xe3\b\xb0W\r\xadU\xcdƻ\x88\xd3$Ƒ\x9c\x9e&!>Gj\xef\x98\x1c\xa7\xae\x90\xed" +
+			"m5\x8e\x9bgÖ\xa6I\x16\xedm\x95F\xcd\x016\x0f!\xf80MBjs\x84ڪ\x18KT\x96\x02C\xfe]k\xe5\x1a\n\b\xc1[Z:X\t\x00\x199x\xd7Ty_\x16K\xb5\x85q\xfc\xa4,\xb49V_\xbd%\xc1\x9d:\x92\xfe\x85`\x1c\xea\x9ab\xbcR\xbc\xcf \xa4C\x05o\xc1\x9a\xc8\x11b\xa2\xda\\\xcb\xc8>" +
+			"\xf9\xbb\x87\x7fw\x8f\xff\xe5A\xf0\a\b\x83\xa5\xb8\x02Ru\vMP\x8e\x8dk \x98\xa6\xe5\b\xde\x01\xb7\x04\xe9N#\x9cZ\x1f\t8\xdd\x14DV\x81\xa3\x008\x19n\xc1p\x84>\xd0\xc1\xbco\u0e65L9wҶ\xf5\xae\xa1\xc8\xd0)\xae\xdbD>\x8f\x82\xea{k(n\xe0)\x8b\t\x00\x15\bd\xed5U\x81\x94\x96E\xfe" +
+			"\\-\x10i\xc3W\x90&KL\v\b\xca\xe9t\xfb\xb9P\xba3ni\xfc\x95\\\x9c3y>\x1fi`\x0fC\xa4\x00Nu\xe9\xecM\xf0C\x9f\f\xfc6o\xdfe`Y\xff}\x05\xd67\ri0.oŏ\xb1\\]\xc6\xc0\a\x01@G\ng\xef\xe82ssio\x84,\xfaJ\by\xf0\xa1\x839\xa0%\x16o\x85\xaa-B" +
+			"G\xdcz]\xe2\xff\x8f\xbb\xe79D\xc6\xf5\x03\x03\x9f{*\xb15Z\x93\xc3l\xb7\xc4:\x86\xc3\x1b\xfb\x1f\t9*;P\x89\xe3\xb8\xf9{\xf7\xf4\xcf4ͻ_\xf2\x93\xd4\xd6\xf98\xb9\x05 \x99\xdeY\x05R\xdf&\x96\xf4 \x18]b\xf4C\xa8\xe9\"w\xa9\x82?\xc5\x12\xff\xfc\x03\xd3{\xd9e0=\x98\v]\x16\x9e\xf3\x96\xbe\xf6" +
+			"\x03\xb3w\x8b\xff8\xec;\xc3xQܳ\x83=\xbbu\x1fL\xa7\xc2\x19\xab\x14|Y\xcc+\x95\x90E\xb2T\xe5\xb8>\xbc\xab\xae\xb7\xb4\xcdw'\xfb@Ջ\x00\x18q\x8e\x10n\x01q\x05\xa8\xac\xf5'\xdc\u0088)7\xb8\x85\x17\xbc\xc1\xd7\x15`\xcaL.\xe7\xbf*c9\x1a\x19T\xd6Ԅ\xafӴ\xba\xe2\xec\x839*\xa6\xe2;" +
+			"\xf7\aټ\xb7\x02\xbc;\xa8\xce\xd8s\xa2\x10\xaf\xb2H\xf6\xc4\xc7k\xfb9\x00\x9c\xe3\xf6ܓ\x04\x00\x00",
 	},
 
 	"/templates/all-pages.html": {
 		local: "resources/templates/all-pages.html",
 		size:  285,
-		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xffL\x8f=\x8e\xc4 \f\x85{Na\xd1'hەC\xb77\xd8\v\xa0\xe0$H\bF\x86\x99\x06q\xf7\x01\x92\xf9i0O\xef\xe9{v)\x966\x17\b\xe4\xcd\xec4\x995\xbb\x18\x92\xacU\x94B\xc1\xb6)>\x915\x86L!wW\xe0\xf1\xa3K\x99\xff]\xf6T+\xaa&{\xd4m0\xff1G" +
-			"\x1e\x19\xeb\x1e\xb0z\x93\xd2\"\x8d'\xce0\xdeɚ\xb0\x13K\xe0\xe8\xe9r\xa4\x16\x00\x982ǰ\xeb\x01@u\xa9_h=/&\xaa\xc6\x1cM\xe4\x13\x8d\x92\xbb\xd7Mrg¹O\xeaA\xef4\x1a8\x98\xb6E\xaaF\xa8U\xea1P\x19\x8d\xaa\xd9\xef\x13Qu\xc6\xf7\xc5\xe7\xe7\x19\x00\x00\xff\xff\x11\xc7\xf8P\x1d\x01\x00\x00",
+		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xffL\x8fMn\xc4 \f\x85\xf7\x9c\xc2b?A\xb3\xad<\xde\xf5\x06\xbd\x00\nN\x82\x84\xa02t6\x16w\xaf Q\xda\r?\xf2\xa7\xef\xf9\xa9\x06\xdebf\xb0\xdf~\xe7\x87_[,\xb9\xdaލ*\xe7л1\x7f\xc8Zr\xe3\xdc\xc6\xd4\xe0\xf1$\xd5\xe5+\xb6Ľ\xa3;\x9e4и\xc1\xf2)" +
+			"Rd2!\xbeaM\xbe֗\xf5\x89\xa5\xc1<\x1f\xc1\xe7\x9dł\x94\xc4\xd7Ē\x01\xc0ڤ䝦\x00\xdd\xf5\xfb\x00\xd5ۉ.\xc4\xf7L\xe2Ty\x86\xfc$2\xaa2\x9cp\xeeS\a\x98\"\xa1\x87Cx{Y\xa7\xba\xf4ni^\xe8<\xa1K\x91\xee\x8a\xe8\x86\xe3\x7f\xe3\xf3\xf1;\x00\x11\xc7\xf8P\x1d\x01\x00\x00",
+	},
+
+	"/templates/attachments.html": {
+		local: "resources/templates/attachments.html",
+		size:  1692,
+		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\x9cTMo\xd40\x10\xbdﯰ,\x84\xe0\xb0k\xf5Z9\xe6@魥\xa2\x85\vB\xc8\x1bO\x12\xab\x8e\x1d\xecI?\x14\xe5\xbf#\xdb\xc9&[\xa8\xd4\xf6\x92\x9d\x8c3\xef=ϼ\xd9aPPi\v\x84v\xb2\x86\xad,Q;\x1b\xe88n6\\\x92\xc6CUP6\f\xbb\x1b\x8d\x06Ƒ" +
+			"\x92\xd2\xc8\x10\n\xbaGK\xf6h\xb7\n*\xd9\x1b$]o\xcc\xd6\xeb\xbaA\xf2\xa7\xd7\xe5m:\f-%\xde\x19(\xe8\xbeGt\x96\x8a\x1f\x1a\xeeɕ\xac\x813)6\x9ba\x00\xab\"\xd7f\xd1Q:\x8b`1KhN\xc4BNxh\xa51B\"ʲi\xc1b\xe0,\xa78kN\x12\x9e\xae\xc8\xee\x8b\xf7Χr\xa5\xeff" +
+			"\xc1ҀG\x92\x9e[%m\r~֖rTl\b\xe1\x01\xbd\xb3\xb5H\x00\x9cMo\xa7d\x18\x0e\x98\x9c)}\x97\x95\x9b\x00\xe38s^\xca\x16B<G\xb970\x93\xa6\x97\f\x8d\rH\x15\xa3\x18\xfb\x1c\xa4\xb4\x88\xa5\x9ca\xb3\xce]H\x7f\xabܽ}\x9a_\xde9\xcb(\x9cMȉd\xef\xd4c\xcc\x0e\x83\x8fw\\t\xad" +
+			"X9*\xb1\x9e\xed\xbb\xb9\xbf\x9f\xf2\U0010b97f\xef\xadl\xa1\x18\x86\xdd8R\x91~\xe2\xdc8C\xb5\x82*\x9d\x02\xf13\x9d\xfe\xfa\xb0Ԟ\xa6\xccG\xce\xd2\xf9q\xcd|\xa3\xca\xf9\x96L\xac\xff\x95\xa2\xc0\x00\xc2vA\xa5\xa4\x05l\x9c*\xe8\xd5\xd7\xeb\x1b:#\x11µ\xedz$\xf8\xd8AA\x1b\xad\x14XJ\x92|Z\x06_\xfd" +
+			"Fw\x1b3w\xd2\xf4P\xd0H\xf5\xf9\xfa\xdb\xf98\xbe\f\">WŻ\xe3\xba\xec\xee\xa90\xf4\xfbV㳋\x12\xe3\x87@\xc5Y\xba\x19g\xb9\xf6\xd0\x11\x16[r\x98\xb0\x9a&\xec\xf3L\xf3\xb2\xa4L\x1e4g\xc9ab\xb1#\xefĥ#\xab\r\xd9q։e\xd1\xfei\xf9ӎ\xf7\x9dqR=i3\x01[\xe6۵\xbd" +
+			"A\xddI\x8fI\xe8VI\x94\xd9߯l\xff\xba\xfb\xeb5M\xa8\xb5w}75\x98\x1b\xb9\aC*\xe7\vZ\xe9\xb8M\xe7\xda\x00g)=}\xb2\xe6N\xdf\x10\xad\xe6(kȕ\xa9uy\x7f_\xc1\x9af?m\xe9s\xac\b\x0fH\x8f\xd0\xe2ߘw&KI\x10GV\xea\x8c,\xa1qF\x81/\xe8Y\xf6F \xe8\b6@" +
+			"\xa2Z\x92i\xdf&\xb9\x85\x10d\rT\\\xe4\xe0\xcd\xc2g\xa0I\xfb\x01\xf7X\xd6K\xfc\xdfy\xddJ\xffH\xc5\xf7d\xb0\xc5\xf8\xb3\xe5\x17\x8f\xce\xc1\xdf\x01\x001\xa1\xf9 \x9c\x06\x00\x00",
+	},
+
+	"/templates/backlinks.html": {
+		local: "resources/templates/backlinks.html",
+		size:  472,
+		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xffT\x91\xb1n\xc3 \x10\x86w?ŉ\xddAY+\xc2ֵ\xeaPu'\xf6\x19\xa3\\\xc0\x85s: \u07bd\x02'\xa9\xb3\xd8p\x88\xef\xff\xf4\x93\xf3\x88\x93\xf3\bb1\x16{3\xb0\v>\x89R\xbaN\x19\x98#N'!s>|\x99h\x91K\x110\x90I\xe9$\xce\xec\xe1̾\x1fq2+" +
+			"1,+Q\x1f\x9d\x9d\x19~V7\\\xdaa\xba\n\x88\x81\xf0$\xce+s\xf0B\x7f;\xfc\x85OcQI\xa3\xbb.g\xf4c\r\xfb\xf7\x18\x82g\xf4\xbc)\xccG]\xc3\x1d\x13\x96\x02*]\r\x91\xde\xe9(\xb9\x8d\x94\x9c\x8f\r\xe7&8\xbc\xc7\x18b\xbb>\xba\xdb\xc3\xd7\x10F\x86\xf6\xedG\xe3-ƇZ\x9b\t\xdd\x01\xa8\xc41" +
+			"x\xab\x1b@\xc9\xfb\xee\rr~2\x95\x1c\xddm\x13\xa7\x84P\xe3\x9a]jy+\xe9.\xe7X\xf1\xbb\xb9\"\xa7_\xca,E\xe8\xf6\xab%(IN?\x8bP\xb22\xee\xf8\xc6\\\xf4G\x80\xfa8@\xce_\x12p\x80]\x01\a%\x97\xd7\x1e\xb7\xc5\xdf\x00\xb2=\x11A\xd8\x01\x00\x00",
+	},
+
+	"/templates/compare.html": {
+		local: "resources/templates/compare.html",
+		size:  1042,
+		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\xa4S͎\xdb \x10\xbe\xfb)F\x1c\xa2\xf6\xe0X{\xad0\xbd\xb4UOU\xa5n{\xc7f\xb0Q\tx\x01'\x8aX\xbf{\x05\xd8N\xb3\xaaV\xaa\xf6\x92\f\xc6\xf3\xfd\xcd8F\x81R\x19\x042\xf1\x01k\xde\ae\x8d'\xcbRU\x94\xc3\xe8P\xb6\xa4\x89\xf1\xf8\xa8\x82\xc6e!\xd0k\xee}K" +
+			"\xba`\xa0\v\xa6\x16(\xf9\xac\x03L\xb3ֵS\xc3\x18\xe0iV\xfd\xef|\xe9O\x04\x9c\xd5ؒn\x0e\xc1\x1a\xc2~)\xbc\xc0w> m8\xfb'\xc3\xc7\"\xa1\x1d\x95\x0f\xd6]\xdfJ\xf8\xb5\xc0d\xba*F4\"Y\xbb\xb9\xee\xad\thB1<>\xb0\x9b\x10\xa0\xfeĵfBII\x9bR\xd3f|\xc88J\xc2\xf1\xb3s" +
+			"\xd6\xe5>\xa1ΛL\xae\xd1\x05ȿ\xb5\xe0f@\xb7)\xca\xcf\b\xab\x00\xa8\x0fΚ\x81e\x00ڬ\xa7\x0f\x10\xe3\x8eI\x1b\xa1\xceE\xb1\xf6\x98I\xa6\xd4\xfa\xc5\xd9\x13\x14\xfaT.\xcbk\x11\x9e\x15^\x0e\x0e\xcfʧS\x8ck\va{\x99b\xd9(\xde\x19\xbc@Z\x82\xf7[L\x00\xc1\xc2\x7f\x11<\xda\x15\xfe\xd1\x16\xf0\x8a6" +
+			"\x13\xbbO(\xe5Y\xa7N\x9f\xc3\xc8f\xf0\t\x8ey5\x88W\x02\xeb\xeeZ\xa7\x7f\x925\xbc& a\x1d\xa4\xb3\xa7\x9b\xbbC\xb0\x9b\x92Cbig\xa3\xa4BA\xd8\xcfR$Y\xf0\xbc\x0f\xe1\x87\x12\b\xdd\x15\x12\xe1>\x8b\xackM\xfe6\xaf\x1d`=\xc3\xf3\x9b\xc5\xdd\xd9}\xa1\x85\xaf2\x8c\xb8[\x884\xfbOJ\xcae)\xb1*" +
+			"ђΊkν\\\xac/\xef\x16\xe8ľY\xe8Ǵ\x8f\xfe\x98G\xf2ק\xf0\xa2\xf83\x00\x0f\x93\xc8\xeb\x12\x04\x00\x00",
 	},
 
 	"/templates/deleted.html": {
 		local: "resources/templates/deleted.html",
 		size:  307,
-		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xffD\x90An\xc4 \fE\xf7\x9c\xc2b?\x83\xba\xad\x1c\xba\xea\rz\x01\x948\t\x12\x82\xca\xd0J\x95\xc5\xddkȌf\x03\x98\xffy\x1f[d\xa3=f\x02\xfb\x1d\x0e\xba\x85\xb5Œ\xab\xed݈P\xdet7\xe6\xe5YKn\x94ې\r\x9eo^\xe4\xfe\x15[\xa2\xde\xd1i9\xacq\x87\xfb'" +
-			"s\xe1\xe9\xd9\xe2/\xac)Ժؐ\x88\x1b\xcc\xf5\xb6\x85|\x10[\xe0\x92\xe8\xa1Xo\x00\xb06.\xf9\xf0\x13\x80\xeeQ\xbd\x83\xe6<\x99\xe8\x949\x93(U\x9a!?i\xbc\x15\xe1A\x85\xebGU\x15\xe5\xa5\xe81\xc0ɴ/\xd6)\xa5\xf7\x8f\xab\xc5匵\x15\xfe\xb3~ޢ\v\x1e\x9d\xba'\xe8j\x1c\xdd\x00\xbf\xe6\xf0<\xfc" +
-			"\a\x00\x00\xff\xff\x87\xaf\x8b\xb23\x01\x00\x00",
+		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xffD\x8fAj\x04!\x10E\xf7\x9e\xa2p?-\xb3\r\xb6Y\xe5\x06\xb9\x80tWw\v\x85\x86\xd2\x19\bE\xdd=\xa8\xc3d#\x96\xff\xfb~}\x91\x1d\x8f\x94\x11\xecO<\xf1\x16\xb7\x96J\xaeVՈ`\xdeU\x8d1\xff\x9e\xad䆹u\xd9\xf8\xeb\x1eD\x96\xef\xd4\bU\xbd\xbb\xee\xa1[\xd3\x01\xcb" +
+			"\x17s\xe1\xe1\xd9\xd3\x136\x8a\xb5\xae6\x12r\x83q\xde\xf6\x98Od\v\\\b_\x8a\r\x06\xc0\xd7\xc6%\x9fa\x00\xbc{M\x1f \xf2fz\xb7\xa7\xe7HB\xaa8B\x1e\xd4\xff\x8ap\xa7\xc2ܨ\xaav\x1e\xa5\xe0#\\\x8c\xc7j\x9dȢ\xfa9+\xaeW\xaa\xad\xf0\xaf\r\xe3ջ\x18\xbc\xa34A\xb3\xb8w\x0f\x9aAc|_" +
+			"\xfe\x06\x00\x87\xaf\x8b\xb23\x01\x00\x00",
 	},
 
 	"/templates/diff.html": {
-		local:      "resources/templates/diff.html",
-		size:       82,
-		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\xaa\xaeVHIM\xcb\xccKUPJM\xc9,\xd1M\xce\xcf+I\xcd+QR\xa8\xad\xe5\xe2\xb2I\xc9,S\xc8L\xb1UJ\xcaO\xa9T\xb2\xb3)(J\xb5\xab\xae\xd6s\x02\xf2jkm\xf4A\\\x1b}\xa0\x1a;..\xa09\xa9y) ]\x80\x00\x00\x00\xff\xff\x13!?nR\x00\x00\x00",
+		local: "resources/templates/diff.html",
+		size:  540,
+		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\xac\x91\xb1n\xc3 \x10\x86w?ŉ\xdd\xf1\v`\x86\xaas\x97\xa8\xdd\xc1w4\xa7\x92\xc3-ة\x85x\xf7ʉc\xb5R\xc6N\x9c\U001097cf\xfbK\x01$\xcfB\xa0\b9\xb7C\x94L\x92\x15\xd4\xda4\x1ay\x86!ؔz\x85\xec};3]\x922\r\x80\x0e\xd6Q\xb8\xc3/\x8b\x1c[" +
+			"\x96\xc0B\xcah\x96qʐ\x97\x916\xa4@\xec\x99z\xb5\xdeW0\xdb0Q\xaf&aτ\xaa\x14\xf6@\x9fpxf\xefߘ.\xb0\xa3Za8\xd1\xf0AX\n\t\xd6j\xe0\xf5\x86tw\x15\xf8'\x95\xc4H\xad[\xda\xf5|\xe4\xf3\x87?\x90:2\x12\xb8\x05V\xfe\xdb\xccM9GٞO\x93;sVwQ\x97\x05\\\x96" +
+			"\x16\xc9\xdb)\xe4\xeb\xfc\x9d\xeezv\xc8\x1ce\x17\\\xb7\xaf\xcc\xf1\x14/\xba\xbb\x85\x9aFwȳ\xd9Zb앋\xb8(\xd3\\\xfd\x0fO\x11\x97ZK\xd9\a\n\x89jգy\x890\x9c\xac\xbcS:\xe8n4\xdb'\xf6\xb8R\x80\x04\xd7\xfe\x7f\x06\x00\x86\xa8\xb0m\x1c\x02\x00\x00",
 	},
 
 	"/templates/edit.html": {
 		local: "resources/templates/edit.html",
-		size:  271,
-		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xffl\x8f\xb1N\xc60\f\x84\xf7<\x85\xe5\xfd\xe7\x17\x03[\xda\x01f&\x9e $\xfe!R\x12W\x89[\xa8\xa2\xbc;i\xabvb\xb3|\xe7\xfbε:z\xf8D\x80\xe4\xbc\xdc,'\xa1$ؚRZ\xe8WL&\x03\xde\r\xf8\xc9n\xbdm\x1e\x84d\"\x1d\v\x84\xcc?e\xc0\xe7\x17\x04\x1bL\xe9" +
-			"\xe3\x83s\xdcc2\a\x1ck}z\xed\xbe\x0f\x9e\xb3\xa5\xd6\xf4\xfd\xcc\x1c\x95v~\x19\x15\x80\xf6i\x9aegD*\xc5|\x11\x82\xacS'lޓvI\xffa`\n\xc6\xd27\aGy\xc07\x8e\xd1\v\\\x17\x8b\tsO\xe8U\x0e\xe5\xfd\x10Z\xc3^⾷P\xb5Rr\xfd\xe9\xbf\x00\x00\x00\xff\xff?S\x83m\x0f\x01\x00\x00",
+		size:  919,
+		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\x84R\xbd\x8e\xdc<\f\xec\xfd\x14\x84\x8a\xaf\xdb5>\xe0\x92\xe2Nv\x91E\xcaT\xa9Rr%\xae-D\x96\f\x8a\xb6\xb30\xfc\xee\x81\xfc\xb38$٤\x90`k8\xc3\xc1\x90\xf3l\xe9\xe6\x02\x81\"\xeb\xe4db\x10\n\xa2\x96\xa5(\xe6yr\xd2\xc2\xf9\x12\xc3\xcd;#\xcbRh\xebF0\x1eS" +
+			"\xaa\x14zb\x81\xf5>M\xc8\xc1\x85F\x01GO;\xa4\xea\x02@'\xe1\x18\x9a\xfa\xb3u\x02f\x17\xd2\xe5\xfe\xfa\nҺ\x04=6\x04\x13&0-\x86\x86,$\x17\f\xc1=\x0e\x90\x04Y\xc8\x16\x00ٞ\v\r89÷8\xf0^\x9c\xc0\xc4\xc1[\bQ\xe0J\xd0\x11g\x01\x1c$v(Π\xf7\xf7W`Jя\x04\xd2R" +
+			"\x01\xd0#K\x82+\xc9D\x14\xf2\x1bh\x13-\xd5\xffyy{v\xb2\x19N\xba\\\v\x01Cv\xb4\xb3\x1ay{v\xc0\f\xcc\x14\xe4\xe0u\xc8߉S\xe6C\u0091\x00\x1bt\xe1\\\xe8Һ\xb1.時\xcdɿ\x8f9\rMCiOS\xe8\x87 \x13\x82\xb3\x95\xbaF{?\xe5X\x14\x04\xech{\xc8#\x98R\xa5\xfe\xff\xa0" +
+			"\x0e\x85[\xe4n\x1d,G\xaf\xeay>\x7f\x8a\xf6\xfe5\x0elhYtyhև\r\xbd\xde\x00څ~\x90\xb5UG)aC\n\xe4\xdeS\xa52\xe5h\xfa\x80\xfe\xd4\rz\x8f\x86\xda\xe8-q\xa5.\xb1\xeb\x9c\xc0\x831\xa2\x1f\xa8R\xf3|ސ/\x1b\xb0,\xea\xe1\xe5_K\xc8qz\xac\xd5\x16\xd1;\xd0D\x7f\xea\xec\xe9\xe3" +
+			"\n\x00\xe8\xf6\xa5^7g$N.\x06]\xb6/;\xd23\xe5`2\x9ar&\xf9?\x8b\x95G\x14\x7fW\xbdlc~.\xbc\x17\xfc.\xfd\xeb䏏\x9f\x03\x00R\xfek\x98\x97\x03\x00\x00",
 	},
 
 	"/templates/history.html": {
 		local: "resources/templates/history.html",
-		size:  719,
-		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\x8c\x921\xef\xd30\x10\xc5\xf7|\n\xcbB\b\x864bE\xa9\x19(\x03\x03\x12\x03bw\xe3kb\xe1\xd8\u007f\xecKQe\xe5\xbbs>\xe3\xb6H\f\f\xad\xce/\xf6ｳ/g\x03\x17\xebA\xc8\x17=C\xaf'\xb4\xc1'\xb9\xef]7j\xb1D\xb8\x1c\xe5\x90\xf3\xe1\x9bE\a\xfb.\xc5\xe4tJ" +
-			"GyF/\xe8\xd7\xd3i\xbd9\x14/\x9bs}\xb4\xf3\x82\xe2\xe7f\xa7\x1f\xfc1\xadR\xc4\xe0\x80\xb6o\x88\xc1K\xf5\xdd\xc2/\xf1\x95\x9c\xc6A\xab\xae\xcb\x19\xbc)^\x8f\x18S\xf0\b\x1ek\x82\xe5\x9dzx\x8b1\xad\xda9\xb5\u0604!\xdeơ.ǁv\x15\x82\xbd\x88ç\x18C\xe4\xa3\xc6^[V\xed \xa2\xe0\xff\xdeh" +
-			"?Cl\xb1X\x93\xaa\x13\xc4\xc6\x18\xfc\xac\x18@\xe8\xbaz/Ⱦ1ǁ\x985\xb4K\xc0&\xa8\xcf\x0e\x9a\r/*\f\x17ЦT\xa5\x8e\xb5`Y\x9d4R\xefT<i_ %\xbe\x92&SŇ\x8a\xc2 f\x9e\x83\xb9\x155\xe7X\x9a\x10\x87\x8fa]-&JR\xc4\xd2\xfe\t\x1c \xb0p\xf7\x1dєK,\xc6\xfb" +
-			"ND\xf3\x90\xdf\x18\xde\xff\xb6\xa9\xcd\xf7\xde\xe1\xffa\x9e\a\xe5U{\xad\x0fu\x94\x8eWz\xf1\xd7\x11\xae6\x95\x15\x11>\x9fh\x8c\n\xeaOۅ\xa6\xd5?\"\xf0d\xb0R;\xbf\x0f\v)\xe5\xaa\xff\x9e\x9fZ\xfc\x0e\x00\x00\xff\xff\x81\x9c\xe7\x17\xcf\x02\x00\x00",
+		size:  1664,
+		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\x94TM\x8f\xe36\f\xbd\xfbW\x10B\x10\xec\x02MܹNm\x17\x8b\x9di\xd1C\x8bbwл\x12ѱ\xb0\x96\xe5\x95\xe8L\a\x82\xfe{AɎ\x93\xf4\xfb\x12PO\x0e\xf9\x1e\xf5\xc8\x10\x14\xb6z@\x10\xa3<\xe1N\x1eI\xdb\xc1\x8b\x18\x8b\xa2\x92\xd09lkQ\x86\xb0\x7f\xd1\xd4c\x8c\x02" +
+			"\x8e\xbd\xf4\xbe\x16\a\x1a\xe0@\xc3Na+\xa7\x9e`\x9c\xfa~\xe7\xf4\xa9#\xf8:\xe9\xe3\x97t\xe9\x8d\x00g{\xac\xc5a\"\xb2\x83h~\xd3\xf8\n\xbf\xca\x13V\xa5l\x8a\"\x04\x1c\x14\xd7Zi\x1c\xed@8Pf\xd0=4km\xa8\xbc\x91}\xdftړuoU\x99\x8fU\xd9=4EQ\x8d\v\xb5\x16Qy\xd1\x14\x00\x7f\xa5" +
+			"\xe0\xfb,\xb1\x9e\xb3l[댤Z\x925\xa2\xf9@\xd603\xd8\x1a\xad\x94\xa5\xef\xfe_\x12\xe7\xbdh>}\xfe\x9c\xc4U\xe5\x98\x14\xea\x16\xf6\xcf\xceY\x97\x14)}^x\xca\x1e\x1dA\xfa\xdd)9\x9c\xd0-\xddJXV\xe0\xc9\xd9\xe1Ԥ\x04U9\x9f\x1e!\x84KΪT\xfa\x9c{\xd9{LE\x98\x0e\xcc\x14o_\xcf " +
+			"uV\xd5\xe2\xc7\xe7\x17\xd1\x14\x95\x1eƉ\x80\xdeF\xacE\xa7\x95\xc2A\xc0 \rSH\xff\x16p\x96\xfd\x84\xb5P\xbam\x05w\x99\xe4\xa1\xc7EA:d\x9eԡT\x1cq\xecr\x90\xe0\xe6\a\xc7=\xa5\xee\x1a{\xb1\xf7ȓ$\xbc\xc7>L\xd4Yw\x8f\xfe\x8c\xde'\x03\xdd\xc2\xeb\xb9*3\x81\xaa\x9cIqL\a\xab\xde\x18\r" +
+			"\xc1q\xafa\xa3\xbf\x81\xcd\xd1\x1a\xa3\t\x1ek\xd8\x7fL\xa1\x8f\xb1\xb8RP\x91jn\x9a\xe4\xa4\xd2v\xe9Q묹t(\x84\xfdOO1\x8a\xf4\xe0\xf8\x156\x1a\x1eb\x84c\x87\xc7/\xa8f\xa33K\xf5_R\x93\xfd\xa7\xc4\xdf\xfe[\xe2\x10\xf6\xdc\xd0\x18\xefa6cn병\xba\x8f\xf1\xe2n>\x92}\f\xe1\xf6^\x00\xb1" +
+			"wj\xf1\xa7\x8b\xe6\x82\xfc\"M*%\x9bŃwW3ɕL&\xf2\x84=2\xc9\v\xbfw*!\xef\xaf?\x9cM\xbd|\xb2\xbc\xf8\xd5Pr\xaa\xd9\xe0\xab\xd5W&\x9b+hP\xeb\xec\x9e5\xben\x1d\x9e\xb5\xe7\xd3\xd2eV5;,K\x9a\v\xe62\x9f\x90\x9fG\xb1\xa5c\xcc\xeb\xe82\v\xf8;\xed\xccD\xa8D\xf3\xce\xe5" +
+			"\xef\x80\x1d³z\xf3\xbf\xf7\xcb\xe6Z\xb6\xdfl\xdaU\xf2\x8a\xb2I\xae\xa4n\xee\x17\x10\xcf\xe5\x96\xecJ\x9f\x01x\xd5\xd4\xc1\xc8\xda\xec\xe4YĒ}\x19\x8d\xb5FUΓQTe\x1ag\x8e\xf2\xb6\x9e}駃\xd1\xf4w\x8b_4\x1f\xad\x19\xa5C\xf0\xd8\xe3\x91P\xc1\xd2S_\x959\x11\xefB^I\xb7\v?\a\x7f\f\x00" +
+			"\xe6,\x19ր\x06\x00\x00",
+	},
+
+	"/templates/login.html": {
+		local: "resources/templates/login.html",
+		size:  757,
+		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\x94\x92A\x8f\x9d \x10\xc7\xef|\x8a\xc9\xdcߚ\xbd6ȥiOM\xbb\xe9n\xcf\x1b\x94\xd1G\x8a``|\xdd\r\xe1\xbb7\xa2[\xeda\xd3\xf4bЙ\xffo~ 9\x1b\x1a\xac'\xc0Y\x8ft\xd1=\xdb\xe0\x13\x96\"r&oJ\x11\xe2h\xe9\x83g\xf2\xbcV\x85\xbcޫ\x9c\xef\x9e,;" +
+			"*E6\xd7{\xb5\xb6\xda\x01\xee>\xc5\x18b)B\x1a{\x83\xde\xe9\x94ZԎ\"C}^\x8c\xf6#E\x84\x18\x1c\xed\x15T\x02@&\x8e\xc1\x8f\xaa\xe6e\xb3\xbf}\x80\x9c\x0fdc\xecM\x1dnr\bq\x82ͺ\xc5\xe6\xb9qa\xb4\x1ea\"\xbe\x06\xd3\xe2÷ǧ\x8dm\xfd\xbc0\xf0\xebL-^\xad1\xe4\x11\xbc\x9e\xa8" +
+			"\xc5>\xc5\xe1\x99\xc3\xcf\xf5\xcbM\xbb\x85Z\xcc\xf9\xee\xe3\xe3\xf7ϥ\xfc+\xeb\xe9\x85ϩ\xaf\xf4\xc25\xb5\xc6N\xdb_5/c\f\xcb\\\x89\x00\xd2\xe9\x8e\x1c\f!\xb6\xb8\xa2P\xfdH\x14+U6\xb5\xb6\xf7\x9dgs\x9dvF\xae\x7f$\x06\x87`\xcd\xcey\x13\xab듘\x9e\xa8\x14\x04\xbdp\x18B\xbf\xa4\xba\xb1\xed4\xff\xc7" +
+			"u\xd6)\xfd\nѠz\xd8W\xef\xeb\xfe\xe9}_\xf9hٴ\x0f\xfc\xdfz\xdd\xc2\x1c\xfc\xceMK7\xd9\xe3 :\xf6б\xbf\xcc\xd1N:\xbe\xa2\xfa\x12F\xb0^6[H\t٬\x83\x95x\xbb6\xe2\xf7\x00\xb0˟\x98\xf5\x02\x00\x00",
 	},
 
 	"/templates/preview.html": {
 		local:      "resources/templates/preview.html",
 		size:       67,
-		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\xaa\xaeNIM\xcb\xccKUPJM\xc9,\xd1M\xce\xcf+I\xcd+Q\xaa\xad\xe5\xe2\xb2I\xc9,S\xc8L\xb1UJ\xcaO\xa9T\xb2\xab\xae\xd6s\x022jkm\xf4\x81\x12v\\\\\xd5թy)@\x85\x80\x00\x00\x00\xff\xff\x97\xb37~C\x00\x00\x00",
+		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\x04\xc0\xb1\t\xc40\f\x05\xd0^S\x18\xf5wY\xc0q\x91Q\x92\xaf\x80\x1a\xb9\x11\x06#\xfe\xeeyU\xb0\xd7Ú\x1a<\x7fό\xb4H%E:|5ǩ\xf7\xc4\xd6Q\xf5\xbf&6\xd9\x0f\xf8\x1a\"U\x16 \xe5\x1b\x00\x97\xb37~C\x00\x00\x00",
 	},
 
 	"/templates/printable.html": {
 		local: "resources/templates/printable.html",
 		size:  436,
-		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xffTQMO\x021\x10\xbd\xf3+j\xcf\xee6\xc8Ř-\x89\"\aOz\xc0D\x8f\xa5\x1d\xe8\xc4~\xac\xdba\x91\x10\xfe\xbb-\v\n\xa7}\xf3\xe6\xbd7/\xdb\xe6\xe6\xf9u\xb6\xf8|\x9b3K\xdeMGM\xf90\xa7\xc2Zr\b|:b\xac\xb1\xa0L\x01\x19z ŴU]\x02\x92|C\xab" +
-			"\xea\x9e_\xae,Q[\xc1\xf7\x06{\xc9?\xaa\xf7\xc7j\x16}\xab\b\x97\x0e8\xd31\x10\x84\xec{\x99K0k\xb8r\x06\xe5A\xf2\x1ea\xdbƎ.\xc4[4d\xa5\x81\x1e5T\xc7\xe1\x96a@B媤\x95\x039\xceAC\x12!9\x98\xee\xf7\xf5\xa2\x80á\x11\x03sZ;\f_\xac\x03'y\xa2\x9d\x83d\x01\xf2%\xdb" +
-			"\xc1J\xf2\xd2<=\b\xe1Տ6\xa1^\xc6H\x89:ՖAG/\xfe\b1\xa9'\xf5\x9d\xd0)\xfds\xb5ǬJi(҈\xf3\x1fk\x96\xd1\xec\xce\xd7\xed\xf8\xaaY\x1e\a\xde`\xcf\xd0H^\xb4\xbcH\x9e2(\x8a\xbc8\xe5\r1\xd9s|\xa3\xdf\x00\x00\x00\xff\xff\x82\x16\xf8̴\x01\x00\x00",
+		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xffT\x91AO;!\x10\xc5\xef\xfd\x14\xfc\xe7\xfc\xdf%\xb5\x17c`\x13\xad=x\xd2CM\xf4HaZ&\xb2\xb0.ӭM\xd3\xefnp[mO̼\xf9\xf1x\x80\xfa\xf7\xf8<_\xbe\xbf,\x84\xe764\x13U\x16\x11L\xdch\xc0\b\xcdD\b\xe5ѸR\b\xa1Zd#\xac7}Fְ" +
+			"\xe5uu\v\x97#\xcf\xdcU\xf8\xb9\xa5A\xc3[\xf5z_\xcdS\xdb\x19\xa6U@\x106E\xc6\xc8\x1a\x9e\x16\x1a\xdd\x06\xafvFӢ\x86\x81pץ\x9e/\xe0\x1d9\xf6\xda\xe1@\x16\xab\x9f濠HL&Tٚ\x80z\n\xcddtb\xe2\x80\xcd\xe1P/Kq<*9*\xa7q\xa0\xf8!z\f\x1a2\xef\x03f\x8f\xc8" +
+			" |\x8fk\r%y\xbe\x93\xb25_\xd6\xc5z\x95\x12g\xeeMW\x1a\x9bZ\xf9+\xc8Y=\xabo\xa4\xcd\xf9O\xab[\x8a\xb5\xcdy\f\xa2\xe4\xf9\xc5\xd4*\xb9\xfd\xf9t?\xbdJ槧\xeb;\x1a\x049\r\x85\x85\x82<$\xb7/\x84\xa3\xe1\xe47\xda(9\xfe\xd1\xf7\x00\x82\x16\xf8̴\x01\x00\x00",
+	},
+
+	"/templates/recent-changes.html": {
+		local: "resources/templates/recent-changes.html",
+		size:  2341,
+		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\xa4\x96Qo\xdc6\f\x80\xdf\xfd+\x04=\x1c\xb6\x87;#/{he\x15Œ\x0e\xc3\xd0,h\xb2\xe7Aw\xa2\xef\x04\xc8\xd2M\xa2\xd3\x14\x86\xff\xfb@I\xb6\xcfi\x16t\xedKb\x91\"\xf9\x89\"\xa9\x1b\x06\r\xadq\xc0\xf8Y\x1da\xab\x0eh\xbc\x8b|\x1c\xaba\x00\xa7Ǳ\xaa\x96-\a\xef" +
+			"\x10\x1c\x92\xb6\x12\xa7+9\f\xbb\a\x83\x16\xc6Qԧ+YU\xe2\xcc\x0eV\xc5\xd8\xf0\x16@G.+Ƅb\xa7\x00m\xc3\xeb\xbf\xeb\x00\ap\xb8=\x9c\x94;B|\xd7\xfa\xd0)l\x14\xfa\x8e\xcb\xf7\xe8;Q+\xc96\x9d\xd1\xda\xe3\xdbo\xb2\r1r\xf9\xe9\xfe\x9e,+Q\x9f\t\x82T3\x87\x0f\xdd\xd68k\x1cp\x96O" +
+			"\xf7\x827\xce:\xc0\x93\xd7\r\xff\xed\xe6!Sk\xf3\xb8\xf2q\f\xbe?'\x15cª=X\xd6\xfa\xd0\xf0h\xdc\x01\xb8\xfc\x10\x88>\xc9\xcb\x1e\xe3\xce=2\xfcr\x86\x86k\x85\xc0W\xee(\x95\xc1[Ό\x9e|0\xa7:\x98\x17\x8f\xca\xf6\xd0\xf0a\xd8ݓ`\x1c3V\xad\xcd\xe3\xff\xe2\xeb\x1d\x1a\xcb\xe5\x83\xffn\xba\xec\xa1\xd0" +
+			"\x95\xc5B\xf7\x17\t\xbe\x9bN\xf5x\xf2\x81\xcb\xf7\xe9\xff\x7f#\"<\xe1+\x88\xc5Ma\x9cV\vdv?\x8e\x9c\x9d\xad:\xc0\xc9[\r\xa1ᷪ\x03\xe6\x03\x83NQ\x8eV'\xd8\xf7\x88ޕ\xf0\xb1\xdfwf\x01أc{t[\r\xad\xea-r\xf9\xc1X\x84 \xealD\x85H\x8c\x92zǴlw\x13\x02E\xaf\xaa\xcb" +
+			"\xbc(\v\x01Y\xfa\xbb\xd5T\x85\x81\xb3\xe0-\x14MƉ\x18\xbc;\xca\xe4@\xd4e\xf5\x86\r\xc3\xec\xb3\x10S\xbb\xda\b)\b\xaa\xbd\x85)LZdgx\x02\xa5Kr1\xe4\x8f$\x96\xd7\nA\xd4x\xba\x94ݩ\xe3W\xb2_S\xbb<\x97N\x97\xb7\x96~\x84\x18_p\xb1\xacE\x9d!D]\xc0\xe8\x1b\xf7^\x7f!\xe90\x04" +
+			"\x8a\xc5v9f\x1c\xc7\xea\x02[\xa0\xa6\xe9C\xdc4|P/\xe2\x12+g\xfe\x1a,ЖeRMyZFˢ{W\x06ģ\x81ϛ\x00\x8f&\xd2j\x18v\xbf_S\x85_\x8e;%\xa7\xf9x\x19\xee\x13P\x05j\x1a\x06\xe3(b\xa7\xac\x9d\xef\x01\x9ep\xdb\xf5\b\x9a˟B\xde\xc7\xda\xe0;\xba̕\xddϢN\x96" +
+			"\xab\b\xeb3\x8axVn\xf2\x9c\xdb)\x01\xc0?l\xf7\x87q\x9aq\xa55\x1fǤ\xda\xc6\xfep\x80\x18\xf3\xc9\xd9j\x9bN\xf9\x99w\xe6:\x9cRTd\xb9\xc8\vL\xca\x02\xd9R\x12\x88B\xae\xc9r\x1erE\xdcP[]d\x9a\x96\xe8\xdf\f\xc3Z\xcf\x19RV\x1b\xfe\x95B\xce\x12\xea\xd49\xed\t\ue66a\xe0=\x87ٕ*" +
+			"|\xaey\xed\xf6\xb5i\xdb\r\xdd\f\xdd\xfc\x9d\n\xe0p\x1c7\xe8\x97B\xa0\x1d\xc42\xf9\x9c*y\xee\xc1\\\xa9\x025;xKYj\xf8/\\\xdezVޛ]\xb2\xbc0+\xf7,\xeaR\xff\xa2N}K\xafY?\x97\x10\xbdЁg\vӲ#\xb2\x1d5)\xbbʶ\xd6\xcc\x1b\xa9v}\x1f\xb9|\xe5\x01%o\xe9\x84e\xf3" +
+			"]J\xd3&=?\xcd\xf2\xecl\xd2\xc4o\x96I\xbf\xc9㵹\x1c\xabrcU\bo\xd9-|\x86\x903c\xcd\xfah\xb90>\xfa\x00\xcfi\x1d\xcd\xf6o!\xbd\x85'\xfc!\xca?i\xec\xb3M ֗(E\xdd\xdb<J\xa7_>\xf9\xe3\xdf\x01\x00\xe0<~W%\t\x00\x00",
+	},
+
+	"/templates/rename.html": {
+		local: "resources/templates/rename.html",
+		size:  1640,
+		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\xac\x94\xcfo\x9c:\x10\xc7\xef\xfc\x15#\xdf7(RNOƑ^\xf4\xde)M\xa3M\xda\x1eW\x06\x0f`\xad\xb1\xa91\xbb\x89\x10\xff{e\x9b_\x9b\xb4RS\xf5\x02\xb6\xc73\xf3\x99a\xf8\x0e\x83\xc0Rj\x04\xd2\xf2\nw\xbcp\xd2莌c\x92P\x0e\xb5\xc52#\xe90\\=K\xa7p\x1c" +
+			"\t\x14\x8aw]Fr\xa7!wz'\xb0\xe4\xbdr\xd0\xf6J\xed\xac\xacj\a\xdf{Y\x1c\x83\xb1k\bX\xa30#y\xef\x9cф}\x95x\x86G^!M9K\x92a@-|\xaed\xe5(\x8cv\xa8]D\xa8\xafٚ\x1ch\xd7p\xa5\x98E\xcd\x1b\xa4i\xdcѴ\xbe\x0e\xa1d\tW\xffYk\xec8&T\xc8ӌ\xca" +
+			"\x15Z\a\xe1\xb9\x13\\Whg\xaapFX\x02@;g\x8d\xaeX\xf0\xa7\xe9\xb4\xfb\a\x86a\r\x99\nyb+2-\x8dm 6\xec\xa2G\xb7\xd3Y\xc4$Р\xab\x8d\xc8\xc8\xe3\xe7\xa7\xe7\x98L\xea\xb6w\xe0^[\xccH-\x85@M\xc0\xdf\xcdH\xd1\xd9\xf2\xe0\xccџ\x9c\xb8\xea1#\xc3pu\xf7\xb4\xff\x7f\x1c\xa3\xef\xa6" +
+			"0\x0f\xb0\xab\xac\xe9\xdb`\x02\xa0\x8a稠46#\x1a\xcf\a\xe7\x81\b{\xc03\x84%MÅ\xe9\xf2\x96\xc2\xe1\x8b#\x17q\xfdg\xb0F\x11\x90b\x1bl\xe2\xdc\x1c\xac\x98\x0fx^Ƅ\xf7Δ\xa6\xe8\xbb\x00\x1d[\xf7\x11\xfc\x06\xbb\x8eWHا\xb8\xf8c\xf49\xd0\x04\xbel7\xdd5M#ݔǳ\xb7\x8a\x17X" +
+			"\x1b%\xd0fd\x1f\xbe\"l~\x81_WT\xd4X\x1cs\xf3rQO\\\xbf\x81^nNX\x16\x85\xb4X\xb8\x85\xcbh\x12'z?Y\xc6\x11\x82\x13\x8ai\x02\x19\xdc#?!p\x98\x9d!\xc7Zj\x11s\xaf\xfd\xfa۬}+\xb8Ã\x92\xfaؽ\xe7\xfd\x12\xac\xf7\xde\xf8\x13\xe4h\x85\xe0\vR\x83q5Z\xf0\xd2\xd3\xfd\x1e" +
+			"v\xcb5*\b\xcfY{\xe6\n\xde\xde\xda\xe5F\xbc\x92\xa5\xa4(ASM]\x9f7ҽS\xb3\xd6ʆ\xdbW\xc2\xf6\x93\xc4D\xa7%\xc6\a\x14\x91\xb0;\xae\vTA\xe8\x00\x96z\x96\x05M\xfd\xb0.\xca\xf5//\x8e*v-\xa1\xf5\r\xf3\"مFI]\x813\xf0^\aW\x9cC\x9a\xcf\xee\xb7\xe1\xb7̶\xf3\xfa\xad\xe6" +
+			"njy\x8d6H\xefF<oXB\xfb\xd0\xf1a\xb0^\x1e/Y\x00\xa8\x92\xec\xa2r\x1f3\xbcb$%\xa3s\xd4E\x9a\xfa`\xf3nY\xfc\x18\x00\f^;\xbeh\x06\x00\x00",
 	},
 
 	"/templates/search.html": {
 		local: "resources/templates/search.html",
-		size:  482,
-		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xffd\x91\xcfj\xc4 \x10\x87\xefy\x8a\xc1\xfbFz-nn\xbd\x95B\xff\xbc\x80\xc4I\"\x88\x86\xd1\x14\x8a\xe4\xdd\xeb\xe8f7\xb0\x97Df~\xf3}f\x92\xb3\xc1\xc9z\x04\xb1\xea\x19/zL6\xf8(\xf6\xbd\xcb\x19\xbd)ﮜ\xe0\b\x8d\xc1'\xf4I\x007\xd4\xf22|\xa3\xa6q\x01¸\xb9" +
-			"\x14a\n\x04*&\n~\x1er\xee?7\xa4\xbf}W\xf2VR\xb2\x8c0\xd0Nп\x11\x05\xaa\x1cc\u007fat:ƫ\xd0\x0e)A}^\x8c\xf63\x92\x00\n\x0eo\x1d1tp\x17T\xc0\x9d\xfd\nEx0\x95,\xccjB\x17\x91%\xcdٮ\xfb\xd5n[ݛcd\xceĲ\xe7\x80r\xb6*5,\x84\xd3U\xc8\"\xf9" +
-			"\xb1\xc9\x15\xa6\x18\x1eg%u\x8d5ډ\xf7^\xd6\xc6\x1c.2\xab\x8cp\xbaQ9ז\\\x9a\x92g[稶\xda\xe9#\xd4:|\x84Ӳ7oz%זi\u007f\xeb\xe9\xf0\x1f\x00\x00\xff\xff\xee\xe0H\x80\xe2\x01\x00\x00",
+		size:  2626,
+		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\xacV\xc1n\xe36\x10\xbd\xfb+\x06<\x14\xed\xc1\x16\x02쩠\xd5Cw\x91\x16\xe8\xb6\xdb$\xc0\x1e\vZ\x1c[\xc4Ҥ\x96\x1c9\x0e\b\xfe{A\x91\x92\xac\xc4\xc9\x02\xed^\x04if8\xf3\xf8\xe6q\xa8\x10$\xee\x95A`\x9d8\xe0Z4\xa4\xac\xf1,\xc6U\bhd\x8c\xab\xd5*\x04\x18\x83" +
+			"\x1ak\b\r1H\x0e\xde\xde\xd4\xf7(\\ӂC\xdfk\xf2\xb0\xb7\x0e\xb8'g͡\x0ea\xf3w\x8f\xee)F^\x15\x13\xafڛz\xb5\xe2{\xeb\x8e\xd0h\xe1\xfd\x96\xa5\xf7\xb52:\xe5\xf7C\xb6\xb5\xed2\n\xc8p\xb6\xac\xfa\xa7\xca.\x06G\xa4\xd6\xca-\xbb\xfd\xf0\xc0\xea\x15\x00\x97\xea\xb4Hup\xb6\xef\x06\x17\x00W\xa6\xeb" +
+			"\t\xe8\xa9\xc3-#<\x13[\x84\xa6\xdd8\xab\x19\x18q\xc4-\xfb\xca\xe0$t\x8f[6C\xcf5*\xa9N\xdf.\xe6QcCoU8Z\x89%\x1a\x80\xe7m\x8e5\x1f\xad\x93\x9e\x85\xa0\xf6\x80_a\xf3W\xe6`\xf3\xd1J\x04Ơ\xf8c\x84\\\x06eiP\xfd99x\x95\xb3\xbd\x92\\+B'\xf4k\xe9G\xf7\x95\xec\x1f\xce" +
+			"\xa2!H\xdc}\xa3\x84\xc3\x03\x9e_+\x90\x9dW\xd2\xdf\xe1\xa1\xd7\xc2\x01\x9e;\x87\xde+k\x96ex\x95W\xbcچ\xa6\xc5\xe6\xcbΞ\xc7&h\xb1C]/\x1a?\x85\x94.4\xc2\xe3\xd4\xea\x9b\fy\xc2\xfb\xab\xf0x\x8f\xc6+R'\x8c\x11\x86\xc53^\xf8(\xa8i!\xa5\xe0U\xae\xf5\x1d\x91\xa5\x1e\xbf\x8a\xecsk5\xa6f" +
+			"_A5\xf8\xe01+\xe1mX\xff\xf3\x90t\x0e\xf7\xea|yRF|\x9f\x06O\x8c\f:-\x1al\xad\x96\xe8\xb6\xecw\x03i\xb4x\xf0$\x1c)s\x80GE-\xfb\x9e\xb4\xb5ʓuO/\x98\xfb-ۯ\xf0U< \x8c\x04\x89\x1a\teFy\x95\xbd]OdM)\xee\xfb\xddQ\xcd\x1c\xed\xc8\xc0\x8e\xccZ\xe2^\xf4\x9aX" +
+			"\x99\x88\xbcʋ\xea\x15\xaf\x12\x8d\xf5j\x951}pκaz^\xecYht\x04\xc3s-\x859\xa0c\xe0\xac\xc6\xe2\xc9d\x95\x19:$\x98&\xea\xcf\x10\u0094\xb3 N\xa3[{\x84\x05\x05c\xfdb\xb8\xcb\x03{\x00Bb\xa7q\x842|\xe4\x82Ԣ\x90\xa5\v\xe4\xa6cOm\xfdI\x1c\x90W\xd4^\xda\xfe\x10\x84\x9e\xc0\xe1" +
+			"I\xa5c\f\xc7tP\x949<\x8f\x9b\xbfy\x95\xb3\xf2\xaaT\x1a\x8a\xee\xac|J\xd6\x10\\b\xe2\n\xe2\v8\x9c\xe4\x94[@\xebp\xbfeU\b\x9b\aE\x1ac\xfc\xa5\\ \xa3@\xea\xd9\xc5+1\xae\xccļ\xcf2\x88\x91\xfbN\x98\x91\x8eA\r0<\xc7\xce\xd4E0\xbcJ\x81\xf5xO\x96\x1d\xc9\x19\xd8[\x88N\n\x1f\x7f" +
+			"\x18\xb9چ\xb0\xb9+\xef\xe9\xca\ta\xf3^P\x01\xb9\xcc\x19\xc2\xe6ި\xaeC\x8aq\xf4\x8c<\xceHxUh\xe4\xd5\xd0\xcfI\x13C\xbf\xbb\xfaO{qc\xf7Fnx\xd5\xe5\x98|\xe5\xcf\xc1\x99\x9b\xac\xe9\v\xcdd\xf3\xad}\xb0Iv\xdd\xc4\x16\n\xc9\xea[\vd\x87\xe3\xb4lJ\x0eg\xf5\xc5\x0fB6]\xfc\x1f\xa4\xfdv" +
+			"\xf5\x8cdΝ&\xd3\xfa\xd8\x13\xcaD\x90F\xf3\x02\x16\x8c\x97Ϗ/\xbd?\xc1M\x8c\tҸ\xb5\xf4\xeeK\x99LB\xe6\x80\xf7zڍ\xf2\xb4\ue367'\x8dr\xfc7)\xbc\xb1\x85D\x9f\xf3õ\x1a\x1aӾ\xbb*\x02Vg\xfe\x86\xcf\xe1B\x89\x91\x1f\x85\xfb\xb2\xd0g1d\xb8\xb3\xa3`\xceT\xb5\xef2\x8e\xa1I\x93." +
+			"\xbag2\xe9&\x91\xf2*!\x9b\xbfz\xfd\x1f\xa4\xb1|\xf9w\x00\x91g\xb4\x9aB\n\x00\x00",
 	},
 
 	"/templates/view.html": {
 		local: "resources/templates/view.html",
-		size:  1782,
-		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\xbcUMo\xdb0\f=/\xbfBP\x81\xdd\\\xa3w'\x03\xf6\x01\xec2\xach\x8b\xddi\x8b\xb1\x85ɖ'\xcbI\x03#\xff}ԗ\xedf\xcbz\x18\xb6C\vY\xd2{|\xa4\xf8\x98i\x12\xb8\x97\x1d2\xdeC\x8d\x19TV\xean\xe0\xe7\xf3fS\by`\x95\x82a\xd8rat/\xf4\xb1c" +
-			"\xfd\xa8Tfd\xddX\xf6c\x94\xd5w\xbe\xdb0V\x94\xa3\xb5\xbaK\x97K\xdb1\xfaˈ\x19FeY\x02gV\u05f5B\xe6\x0f\x87\x963{ꑮ{0gR,q\xbe`7\xdeq&\xc0BD-g\x9c\x81\x91\x90\xe1s\x0f\x9d@\x02Y3\xa2\xd7AJ\x06\xdaL:ju\xea\x1bY\x91\xb0y\x95U\xba\x8e\xf8F\n\x81" +
-			"]B\x17\xb9C\xfe\x86\xa4\x02\x83v}^\xe4A\xb0_\x8f\xea\xb2BYK\xd293\xdaI\x0ek\x1fNA\x89J\xa1(O\x97YƠJFPop\xc0\u0382{\x89xHǰ\xa2\x94\x16]\xf5\xa0\x94T\x80\xe7-ϨT\x8d\xc1\xfd\x96\xe7\xd3t\xfb$\xad\xc2\xf3\xf9]x\xcb\xedA\xe2\xf1\xed^\x9b\x16\xec\xb67\x92\x88K" +
-			"\x853\xef\x9b\xfb\xb4\xc5\x0eh\x06\x02\xa4\x809Da\xb9\x92\xffQ\xa2\x81\xe3\"\xee\x1b\x1d\xb0G=\x9a\n\xff\x9d\xac\x17M\xd6j\x01*큩\xd1n\xf9\r5\xce^\x9a\x96\x1aZ\xa1Ŕ\xc8͢\xf3\xa3?\xb8&\xb1\xc8G\xb5\xdb\x149\xf9iG\xb6\x82\xeb\x85h\xe4`\xb59\xf1kN\xbat\xdf줐at\xd2\xees\xa0" +
-			"\xf1B\xfe\x18\x0f\x85\xb4\u007f\x1b\xec\x13q\x84Hӄ\x9dp\x93\x83V,\x8d\x15\xaa\x9d\xa57\xe1̏\x94\xe6n\xb7\xa8\x98&\xb9g\xb7\x0fx\x90\xae\xef\xceg2^\vJ\xb9\x1b\xcb&\x19/nz\xf2\"'\x8a\xcd%rS\xb8\xeea1\xab뙶h\x1bM\x13\xe3\xfe\xeb\xe3S\x98\\\xb2\xebG\x1b\aQ\x98\b\x9cu\xd0җ" +
-			"u\x04\x9c\x1d@\x8d\xf4\xb50\xbe\x86+\xb58\xada\x0fp|O[\xaf\x03[\x1c\x06\x9a\xc13\x96\xd2CCW5{Q\x10\xa2q<\xab\xe1L\x83\t\x15\xf3\xff\xd3㥡ry+\xf3\xeafkĹ\x1d\xd4\fc\xd9^o\x87Y\x96\xf1\xb2\xf8.\xc8[O\xc3\xe8\xb6_\xba\xed*\xe5\xee\x03t\x15\xaa\x95a\xbcE\xe6\x05y\xc6" +
-			"\xbd몵|B\xee\x87\"\xe4A1Bm\x13`\x9a\xc8\xe0\xbd\x02K\x9d\x17ܚEGߺ\xfeK4?\x03\x00\x00\xff\xff6\xc5MP\xf6\x06\x00\x00",
+		size:  2828,
+		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\xc4V\xc1n\xdc6\x10=W_1`\x02\xa3=h\x05\x039J2ڴA/E\x03\xdbh\x8f\x01%ή\bS\xa4J\x8d\xd6^\x10\xfa\xf7\x82\x94\xb8Ҫ\xde\xe4P$\xb9,\xb8C\xbe\x997\xc3\xe1\x1b9'p/5\x02\xeb\xf8\x01S^\x934\xbag\xe3\x98$\xb9\x90G\xa8\x15\xef\xfb\x82\t" +
+			"k:a\x9e5t\x83R\xa9\x95\x87\x86\xe0\x9fA\xd6O\xacL\x00\xf2j 2:\x1e\xaeHCE:\x15\xb8\xe7\x83\"\x88\xe0\x94\xcc\xe1\xa0\x10\xc2f\xdf2\xa0S\x87\x05\x9b\xc0\f\xa4X\xe2\xfc\x81z\xb8e 8\xf1\x19\xb5\xec1\xe0V\xf2\x14_:\xae\x05\x8a\x82\x91\x1d0\xf0\x00\xc8\xfb\x8e\x9fy\x1cԩkdm4\x9cWim" +
+			"\x0e3\xbe\x91B\xa0\x8e\xe8<\xf3\xc8W\x9c\xd4\xdc\"\xad\xf7\xf3l\"\x1cփ\xdaV(mQ\x0f\f\xac\xf1\x94\xa7u\b\xa7x\x85J\xa1\xa8N\xdb,\xe7\xa0JΠ\xceb\x8f\x9a\xb8\xbf\x89y\x13 \xe7+\x97\x92\xd0W\x8fWR\v|)Xzˠ\xb1\xb8/X\xe6\xdc\xeeQ\x92\xc2q\xbc\x9b\xee\xb28J|\xbe\xd9\x1b\xdbr" +
+			"*:+5\xf1J\xe1\xd9\xef\x0f\x1f\xa3\t\x8eh{it\f\x98\xf1\x99X\xa6\xe47\xa4h\xf9\xf3B\xee/\x89\xcf\xf0`\x06[\xe3W\xa7\xf5)\xabx\xfd\xa4\xa4~\xea\xef\xc8\xf3+\x16\xa2\v\xa3\xbf\x1bN\x10\x0eA\x83\x16\xbfC\xb18\x11\xaf\x9b\x165\xf5\v\xad\x9f\x17\xe3w\xa0dQ\xf3v\xd5S\xf7\xe1\xff\xd7#r!\v\xad\x11" +
+			"\\E\x1b\xb7\a\xa4\x82\xbd\xa9\x8d\xdeKۦ\x02\x15\x12F\xeao\x16\x8a\xbf\x86\x8dk\x14\xf3lPe\x92gB\x1e\xcb$\xc9\xf9\xf5\xd4\x1bٓ\xb1'vM\xfb\xb6zy־)\xc3Y\xfb\xca\xdf'7\x81\xc8g㡐\xf4\x7f\x83\xfd&$M\x91\x9cC-\xbc\xd6'\xceA\x1c\x04\xb5ф\x9a\x18\xf8\x8d\xbc\xb9-\x17\x16\xce\xc9" +
+			"=\xec\xee\xf1(\xbdR\x8c#\xe4}˕\xf2'\x16c\x9eEcp\x9eg\xcdm\x99D\xa4\x90\x16kB\xf1\xc1\x9av\x1c\x93\xbc\x8b\xb9\x10\xbeP\xda\x0e\x84\x82\x95?.\xe7`oM\v\x17\x15\xd9:\xb9\xb3\xb3\xa1І\x95\xaf\x1c\xf0\xb9\xfe\x94g]y\xce\xf7\x92ͣ\x19ǋi\xc7\x15Z\x82\xf0\x9bJ\xbd7\xb1\x80\xc1\x12z\xe8" +
+			"\xb1\x91=\xf8\x89\t1x\x0fd^\xe7\xe9ݳrc\xf0\x9cv\xb1ö\xb4b%\x93܋\"\xccW\x7f\xbd\x1dZ\xa4ƈ\x82}\xfc\xf3\xe1q\x1a\xc8Rw\x03\xcd\xf3u\x1at\f\xfc\x9b,X\xdd\xdb\xfd'2O\xder\xe4j\xc0\x829\xb7{\xffp\xffa\x1c\xbf\x84\r\xb2\xb8\x86\xad\xe5\xf13\xb8ʈ\xd3\x1avϟ\x7f1" +
+			"\xe2\xf4e`\x8b}\xcf\x0fK\xc8{<\xfa\x9b!\x03\x17\x1d\xc7\xca\xc4\xfbY\xdd`\xc75*\b\xbf\xf1u\xc49\xbb=\x95\x06vg\xed\x99?e&6\xfdP\xb5\xd7\xdfۙ\x96\r\xb4X9\xd1[\x7f \xccr\xf6\x9f\xe7|\xd5e\xf9\x9e\xeb\x1a\xd5J\x91B\x87\x9c\x17I\x9e\xf9\x9eX\xbdݐ\x90\xffv\x9a\xf2pn7\xd56\x02" +
+			"\xa6\xa6Z\r\x885f;L\xf2\xe6]\xb9:\x9agͻ\xf9;g\xa2\xe3\x9c\xe5\xfa\x80[\x7f\xb3\xa8\x97\xebT\xdf^\x1f\\7\xe1v\x9d\xdb͊\xf2vu\x977v^\x16έ\xeds\xba!\xc1\xe9\xf9,\xf3$\x96b#\xdcќ8G\xd8v\x8a\x13\x02\x9b&B:O\x8dݴ=\x9d\xfbw\x00\xa2!\x9c\x9b\f\v\x00\x00",
+	},
+
+	"/templates/webhooks.html": {
+		local: "resources/templates/webhooks.html",
+		size:  1829,
+		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff|U\xc1n\xdb8\x10\xbd\xeb+\x06<Ǣ\x9d\xdd\xf6`P\x02\x82M\n\xecb\xd1\x16q\x17{(\x8a\x82\x16\xc7\x16\x11\x8a\x14ȑ\xd3@п/Hѵcws1\xc8GΛѼ\xe1\xf38*\xdci\x8b\xc0z\xb9ǅlH;\x1b\xd84\x15\xe3\x88VMSQ\x9c\xae4\xce\x12Z" +
+			"\x8a\xa7\x85hW\xf58\x96_4\x19\x9c&\xc1\xdbU\x1d\xaf\xea\x1d\x94\x0f\xde;?M\x85P\xfa\x00\x8d\x91!TL\x1a\xf4\x04\xe9w\xa1\xa4ݣg\xe0\x9d\xc1|\xc2\xea\x02@\x04\xf2\xce\xee\xeb\x14/xޭa\x1cO\x94\\\xe9C}^[L\xb8\x91\aTo$\fC\xd3`\b\x17\x19\xff\xc5m\xeb\xdcS\x80\x10\xc3\xcbKj\xd1" +
+			"ǚ\xee\xe0\xafͧ\x8f`t p;@\xabz\xa7-\x05\xf0ؠ>h\xbb\a\tA\xef-\xaa\xf9f/_\x8c\x93\n\x9e[\xb4 !v\x15t(`\xcer\x03\n\r\x12*p\x1e<Z١*A4Na\x8d\a\xb4\x14\x04O\x1b\xf0\x18\xc8\xeb\x86\x02P\x8b`\x1d\xe9\x9dndR\xa7\x00 \a\xc1u\x18K\x9a\x83#{" +
+			"\x0e\xbd\xc9М\xe9\xc8'\xad\xca\xf8\x9c6\xe3e!x_\x17\x85\xd89\xdf\xc1,\x7f\xc5\xf8w\xfe\x9c\xbbàCj\x9d\xaa\xd8\xe7O\x9b/\xb3N\xda\xf6\x03\x01\xbd\xf4X\xb1V+\x85\x96A\xe4\xacX\x13\xfc\xee;\xb9\xa7\x88\x1c\xa4\x19\xb0b\xe3X\xfe\xb1y\xfc0Ms\xec\x99D1\xe5b\xef\xddЧ#\x00A\xf8\x83\xa4G\xf9" +
+			"\xeaF\x9c:\xef\f\x03\xad*\x16\xdc\xe0\x1b<\xa6;\xee\xbc{\x0e\x15[-Y\x1c\xc9M\x02\xe3L\x1e\xe9R\xe2Y\u07b8\xda\x0eD\xce\xe6\xfað\xed4\xb1c\xc6-Yؒ]\xf4^wҿ\xb0z\x93\x1a;\x87ԅ\u0c64:M\xc7\xc3\x0f\xd9\xf5\x06ש\x81\xa2\xf7X\x7f-\x00F6x\xc3\xd6\xc0Z\xa2>\xac9o" +
+			"ZI%\xcew\xcb\xc6u<\xb5\x95?\xeb'\xcdn\x80\x05l<R\f\b\xbf\xa5\xd5t\xf3K\x16\xfd\x8a\xe3:zI-\xfa\b\xcdc\xc4\xd6\xf0\x95š`ߦ\xe2\x9b౼\xf8ho\xeb{4\xfa\x80^c\x10\xbc\xbd\x8d ɭ\xc1c\x03\xd2f\x96\x8aZ\x94ꨌ\x9f\x17\t\xae\xefe\x1c,jϱ\x87\xfc4\xae\xf0" +
+			"\x03^\x83\x9f\xe5\xfe\x8a\xe0\x8e\b\xbb\x9e\xc2%\xfe\x88a0g\f\x82\xcf\xc5\b\x9e\v\x8ck\xda:\xf5\x12\xd1q\xf4\xd1^\xa0<}\xe74\x15g_ H\x01E\xd7J\xa3\xf9\xe7}\x1c\xccdd\x1d\x96\x1f\x9c\xef$\x01\xbb].\xdf/\x96\xab\xc5\xf2\x16V\xef\xd6\xcb\xdf\xd7\xcbw,\r\xd4\xcfv\xa8\x18\xf3\xcf\xe3߿@\xef\xd2#" +
+			"\xba<\x10\x12Z\x8f\xbb\x8a\xf1\x93k\xb2W\x0e*\xebk\xaaܒK\xb2ܝ\xd9\xfd\xf2\x87F\a\x14\xa1\x97\xf6\xa8\xa4\x91[4\x90~\x7f:`z\x1f$iH\x94\xf1\xf6\x89\vM@8\xf3\xef\xff%\xcb\xfe]\xef\xa46\xa82\u0379K\x9f3\xbeŃ;9\x18bu\x8fVi\xbb\xcfLG\a\xceZ\xab\xac\xb5\xaf\x8b\x13g\x16" +
+			"4j\xd98\x13\xc3*\xf6\x9e\xd5\x1f\x1d\xa8\xb9\x1b\x1a\x03\x04m\x1bL\xf6\x19\x9f\v\x04\x92\x9e\x92\xd1G9N\x849\x99\xe0y\x86\x04Oo \xfd\x9d͇\xff\r\x00\x9b\x82\x8eU%\a\x00\x00",
+	},
+
+	"/templates/worktree.html": {
+		local: "resources/templates/worktree.html",
+		size:  1482,
+		compressed: "\x1f\x8b\b\x00\x00\tn\x88\x00\xff\x94TQk\xe46\x10~\xf7\xaf\x18D\xe0Z\xc8\xda\x1c\xf4\xe9\xb0]hҾ\x94\xd2\xd0\xe4\xfd\xd0J\xe3\xb5XYr\xa5q6\x8b\xd0\x7f/\x92\xec\xdd͑\x96\xdeK\xb2\xd6\xcc7ߧ\x19}\x13\x82\xc4A\x19\x046\xf3\x03\xee\xb8 e\x8dg1V!\xa0\x911V\xd55EXCh(E\xab" +
+			"v\xfc܇P\xbf(\xd2\x18cی\x9f\xfb\x94\xaa\x06\xa8\x7fuκ\x18\xabV\xaaW\x10\x9a{\xdf1\xae\xd1\x11\xe4\xbf;\xc9\xcd\x01\x1d\x03g5\xae\x11\xd6W\x00\xad'g͡\xcf\xf8\xb6Y\xbf\xbe@\bג\x8dT\xaf\xfd\xad\xb6D\xf8h\r\xfe\a\x9f_\x84@\xef\xbf!\fa\xc5}\\\xf3aL*}\xbe\xea\x9cԽ\x8c" +
+			"\xe8\x11\x06\xa5у\x1d\x80F\x04ɉ\x83T\x0e\x05Yw\x86\x13:\x04\x91q\x12\xecB^I\xdcRO\xea\xa8jx\xe2\a\xf4\x15\x80\xe0\xc6X\x82=\x82\xe7\xaf(\xefA\xa2FJ(\a\x0e\r\x9fP\xc2bH\xe9\x8c-%=\xf0T\xdeN\x93\"By_\x01x\xe2~,(\xa9\xbc\xe0N\xa2\xac\xab\xb6\x99\xfb\xaaj\a\xeb&(" +
+			"\xf3\xecX\xf3\xb59Yw$\x87\xc8`B\x1a\xad\xec\xd8ӟ\xcf/lkXJ\xdf)\xa3\x95\xc12\fe慀\xce3vlTR\xa2a\x90\x94uLx7|%{L'\xaf\\/ر\x10\xea\x87\xe7\xbf~\x8b\xb1`o\x06\x91\xeb\x1e\x9c]\xe6\x1c\xfa\xa60\xe1\x1b\xbd\x97\x90ޘ\xb3z\xe3\x9a\xd0{~\xc0[\xa2?\xca" +
+			"Q\x8c\ff\xcd\x05\x8eVKt\x1d{ȭ\x81\r\x91\x85\x94\xd1\x02\xb4\xfb\x85Ț\x95\xd5/\xfbI]y\xf7d`Of7;5qwިK\xef.̥\xf3\xac_i\xb8\a|#t\x86k@\xa9\xa8m\n\xc5\xfff\x938\xf0Eӿ\xb0\xe5Ѳ\xfe9\xfd\xfb\xfeҫ\xc1>\xac\xbc\xbe\x14\x06\xd6\b\xadıc\x0e" +
+			"iq\x06\x845\x83r\xd3\x0f\x9f\x1eKƻ\xb77X\a\ak\xe5ϟ~d\xfd\x9ap\xd5\xd56ivy\x01\xb8\x94\x0fw\xea\x1e\xee\n\x16\xbet\xb7njǟ\x8a\xd7gn6ݚ\xefQC\xf6\x1d\xfe\r\xf5\xef\xcaH`\\J\x16c\x0em\x0e\x0e\x01\xb5Gx\x97V\x9cs\xc9,W/\x89\x97\xb3\xd2\xe9\xd5\xe0\xd9\xf9\t" +
+			"\x9b\x9c\x9fTd9\xc2JL\x81'Nc\n\xe4\xef\xaam\x92\xda\x10N\x8aFPF\xe2\x1b\xdcՏj\x18<ܩ\x18C\xa8cܸ\xday\xbbNzԻi!\x94\xac\xffE\x19\xee\xceym\xd4ɘۖ\xb9\xd96\u06dd\xd2F\xb8,\xba\xb4t^\xd2ް\xee\bɶ\xa0<\b\x8d\xdc\xd4\xc5߷\x05\xf2\x8f\x7f\x06\x00" +
+			"I\xbc\xe5\xd4\xca\x05\x00\x00",
 	},
 
 	"/": {
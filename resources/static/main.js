@@ -20,6 +20,120 @@ $(document).ready(function() {
   });
 
 
+  // - - - - - - - - -
+  // Title suggestions
+  // - - - - - - - - -
+
+  function pageURL(title) {
+    return "/" + title.split("/").map(encodeURIComponent).join("/");
+  }
+
+  // Shows below $input the titles suggested for what query($input) returns,
+  // and calls choose with the one picked.
+  function suggest($input, query, choose) {
+    var $menu = $('<ul class="dropdown-menu"></ul>').insertAfter($input);
+    var timer, request, active = -1;
+
+    function close() {
+      $menu.hide().empty();
+      active = -1;
+    }
+
+    function select(n) {
+      var $items = $menu.children();
+      active = ((n % $items.length) + $items.length) % $items.length;
+      $items.removeClass("active").eq(active).addClass("active");
+    }
+
+    $input.attr("autocomplete", "off");
+
+    $input.on("input", function () {
+      clearTimeout(timer);
+      timer = setTimeout(function () {
+        var q = query($input);
+        if (request) {
+          request.abort();
+        }
+        if (!q) {
+          close();
+          return;
+        }
+        request = $.getJSON("/_/suggest", {q: q}, function (titles) {
+          close();
+          titles.forEach(function (title) {
+            $("<li>").append($("<a>").attr("href", pageURL(title)).text(title)).appendTo($menu);
+          });
+          if (titles.length) {
+            $menu.show();
+          }
+        });
+      }, 150);
+    });
+
+    $input.on("keydown", function (event) {
+      if (!$menu.is(":visible")) {
+        return;
+      }
+      switch (event.which) {
+      case 40: // down
+        select(active + 1);
+        break;
+      case 38: // up
+        select(active - 1);
+        break;
+      case 9: // tab
+      case 13: // enter
+        if (active < 0) {
+          return;
+        }
+        choose($menu.children().eq(active).text());
+        close();
+        break;
+      case 27: // escape
+        close();
+        break;
+      default:
+        return;
+      }
+      event.preventDefault();
+    });
+
+    // Keep the focus on $input when a suggestion is clicked
+    $menu.on("mousedown", "a", function (event) {
+      event.preventDefault();
+      choose($(this).text());
+      close();
+    });
+
+    $input.on("blur", close);
+  }
+
+  // The search box goes to the page picked
+  suggest($("#search"), function ($input) {
+    return $input.val().trim();
+  }, function (title) {
+    window.location.href = pageURL(title);
+  });
+
+  // In the editor, titles are suggested inside [[links]]
+  var openLink = /\[\[([^\[\]|\n]*)$/;
+
+  suggest($("#body-edit"), function ($textarea) {
+    var textarea = $textarea[0];
+    var match = openLink.exec(textarea.value.slice(0, textarea.selectionStart));
+    return match ? match[1].trim() : "";
+  }, function (title) {
+    var textarea = $("#body-edit")[0];
+    var before = textarea.value.slice(0, textarea.selectionStart).replace(openLink, "[[" + title);
+    var after = textarea.value.slice(textarea.selectionStart);
+    if (!/^(\]\]|\|)/.test(after)) {
+      before += "]]";
+    }
+    textarea.value = before + after;
+    textarea.selectionStart = textarea.selectionEnd = before.length;
+  });
+
+
   // - - - - - - - - - -
   // Keyboard shortcuts
   // - - - - - - - - - -
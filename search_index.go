@@ -0,0 +1,1126 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"io/ioutil"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// searchIndexVersion is bumped whenever the format of the index or the
+	// way the words are indexed changes, so that older files get rebuilt.
+	searchIndexVersion = 1
+
+	// searchIndexSaveDelay is how long changes wait to be saved
+	searchIndexSaveDelay = 5 * time.Second
+
+	// Parameters of the BM25 ranking. A word in the title counts as
+	// searchTitleWeight words in the body.
+	searchK1          = 1.2
+	searchB           = 0.75
+	searchTitleWeight = 3
+
+	// snippetWords is the number of words around the matches shown in the
+	// results, along with at most snippetLines matching lines.
+	snippetWords = 30
+	snippetLines = 5
+)
+
+// posting holds the positions of a word in the title and the body of a page.
+type posting struct {
+	Title []int `json:"t,omitempty"`
+	Body  []int `json:"b,omitempty"`
+}
+
+type indexedPage struct {
+	Length int      `json:"length"`
+	Words  []string `json:"words"`
+}
+
+// searchIndexFile is the content of the file the index is saved to.
+type searchIndexFile struct {
+	Version  int                            `json:"version"`
+	Head     string                         `json:"head"`
+	Pages    map[string]indexedPage         `json:"pages"`
+	Postings map[string]map[string]*posting `json:"postings"`
+}
+
+// SearchIndex is a PageStore answering searches from an inverted index of the
+// words of the pages, saved to filename.
+type SearchIndex struct {
+	PageStore
+
+	filename string
+	changed  chan struct{}
+	closing  chan struct{}
+	closed   chan struct{}
+
+	mu          sync.RWMutex
+	head        string
+	pages       map[string]indexedPage
+	postings    map[string]map[string]*posting // word -> title -> positions
+	totalLength int
+}
+
+func NewSearchIndex(store PageStore, filename string) (*SearchIndex, error) {
+	index := &SearchIndex{
+		PageStore: store,
+		filename:  filename,
+		changed:   make(chan struct{}, 1),
+		closing:   make(chan struct{}),
+		closed:    make(chan struct{}),
+	}
+
+	head, err := store.Head()
+	if err != nil {
+		return nil, err
+	}
+	if index.load() != nil || index.head != head {
+		if err := index.rebuild(); err != nil {
+			return nil, err
+		}
+	}
+
+	go index.saveLoop()
+	return index, nil
+}
+
+// Close saves the changes not saved yet and stops saving the index.
+func (i *SearchIndex) Close() {
+	close(i.closing)
+	<-i.closed
+}
+
+// load reads the index saved to the file, ignoring files of older versions.
+func (i *SearchIndex) load() error {
+	content, err := ioutil.ReadFile(i.filename)
+	if err != nil {
+		return err
+	}
+
+	var file searchIndexFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return err
+	}
+	if file.Version != searchIndexVersion {
+		return errors.New("Outdated search index: " + i.filename)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.head, i.pages, i.postings, i.totalLength = file.Head, file.Pages, file.Postings, 0
+	for _, page := range i.pages {
+		i.totalLength += page.Length
+	}
+	return nil
+}
+
+// scheduleSave asks for the index to be saved in the background.
+func (i *SearchIndex) scheduleSave() {
+	select {
+	case i.changed <- struct{}{}:
+	default:
+		// A save is already pending
+	}
+}
+
+// saveLoop saves the index whenever it changed, at most once per
+// searchIndexSaveDelay, until the index is closed.
+func (i *SearchIndex) saveLoop() {
+	defer close(i.closed)
+	for {
+		select {
+		case <-i.changed:
+		case <-i.closing:
+			select {
+			case <-i.changed:
+				i.save()
+			default:
+			}
+			return
+		}
+
+		select {
+		case <-time.After(searchIndexSaveDelay):
+		case <-i.closing:
+		}
+		// The save below covers the changes made while waiting
+		select {
+		case <-i.changed:
+		default:
+		}
+		i.save()
+	}
+}
+
+// save writes the index to the file.
+func (i *SearchIndex) save() {
+	i.mu.RLock()
+	content, err := json.Marshal(searchIndexFile{
+		Version:  searchIndexVersion,
+		Head:     i.head,
+		Pages:    i.pages,
+		Postings: i.postings,
+	})
+	i.mu.RUnlock()
+	if err == nil {
+		err = writeFileAtomic(i.filename, content, 0600)
+	}
+	if err != nil {
+		log.Println("Cannot save search index: " + err.Error())
+	}
+}
+
+func (i *SearchIndex) rebuild() error {
+	head, err := i.PageStore.Head()
+	if err != nil {
+		return err
+	}
+
+	titles, err := i.PageStore.ListPages()
+	if err != nil {
+		return err
+	}
+
+	bodies := make(map[string]string, len(titles))
+	for _, title := range titles {
+		body, err := i.PageStore.PageBody(title, head)
+		if err != nil {
+			return err
+		}
+		bodies[title] = string(body)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.head = head
+	i.pages = make(map[string]indexedPage, len(bodies))
+	i.postings = make(map[string]map[string]*posting)
+	i.totalLength = 0
+	for title, body := range bodies {
+		i.addPage(title, body)
+	}
+	i.scheduleSave()
+	return nil
+}
+
+// removePage removes the words indexed for the page title. The caller must
+// hold the write lock.
+func (i *SearchIndex) removePage(title string) {
+	page, ok := i.pages[title]
+	if !ok {
+		return
+	}
+	for _, word := range page.Words {
+		delete(i.postings[word], title)
+		if len(i.postings[word]) == 0 {
+			delete(i.postings, word)
+		}
+	}
+	i.totalLength -= page.Length
+	delete(i.pages, title)
+}
+
+// addPage indexes the words of the page title. The caller must hold the write
+// lock.
+func (i *SearchIndex) addPage(title string, text string) {
+	postings := make(map[string]*posting)
+	get := func(word string) *posting {
+		if postings[word] == nil {
+			postings[word] = &posting{}
+		}
+		return postings[word]
+	}
+	for n, token := range tokenize(title) {
+		p := get(token.Word)
+		p.Title = append(p.Title, n)
+	}
+	tokens := tokenize(text)
+	for n, token := range tokens {
+		p := get(token.Word)
+		p.Body = append(p.Body, n)
+	}
+
+	page := indexedPage{Length: len(tokens), Words: make([]string, 0, len(postings))}
+	for word, p := range postings {
+		if i.postings[word] == nil {
+			i.postings[word] = make(map[string]*posting)
+		}
+		i.postings[word][title] = p
+		page.Words = append(page.Words, word)
+	}
+	sort.Strings(page.Words)
+	i.pages[title] = page
+	i.totalLength += page.Length
+}
+
+// update reindexes the pages titles written at revision.
+func (i *SearchIndex) update(revision string, titles ...string) {
+	parent, err := i.PageStore.Parent(revision)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	bodies := make(map[string]string, len(titles))
+	for _, title := range titles {
+		body, err := i.PageStore.PageBody(title, revision)
+		if err != nil {
+			// Deleted or renamed
+			continue
+		}
+		bodies[title] = string(body)
+	}
+
+	i.mu.Lock()
+	fresh := i.head == parent
+	if fresh {
+		for _, title := range titles {
+			i.removePage(title)
+			if body, ok := bodies[title]; ok {
+				i.addPage(title, body)
+			}
+		}
+		i.head = revision
+	}
+	i.mu.Unlock()
+
+	if !fresh {
+		if err := i.rebuild(); err != nil {
+			log.Println(err)
+		}
+		return
+	}
+	i.scheduleSave()
+}
+
+func (i *SearchIndex) DeletePage(title string, author Author) (string, error) {
+	revision, err := i.PageStore.DeletePage(title, author)
+	if err != nil {
+		return "", err
+	}
+	i.update(revision, title)
+	return revision, nil
+}
+
+func (i *SearchIndex) RenamePage(title string, newTitle string, message string, redirect bool, updateLinks bool, author Author) (string, error) {
+	revision, err := i.PageStore.RenamePage(title, newTitle, message, redirect, updateLinks, author)
+	if err != nil {
+		return "", err
+	}
+	if updateLinks {
+		// Any page may have been rewritten
+		if err := i.rebuild(); err != nil {
+			log.Println(err)
+		}
+		return revision, nil
+	}
+	i.update(revision, title, newTitle)
+	return revision, nil
+}
+
+func (i *SearchIndex) SetPageBody(title string, body string, message string, baseRevision string, author Author) (string, bool, error) {
+	revision, created, err := i.PageStore.SetPageBody(title, body, message, baseRevision, author)
+	if err != nil {
+		return "", false, err
+	}
+	i.update(revision, title)
+	return revision, created, nil
+}
+
+// SearchHistory returns the latest revision matching q of up to limit pages
+// allowed, deleted ones included, most recent first.
+func (i *SearchIndex) SearchHistory(q string, options SearchOptions, allowed func(string) bool, limit int) ([]HistorySearchResult, error) {
+	match, err := revisionMatcher(q, options)
+	if err != nil || match == nil {
+		return nil, err
+	}
+
+	changes, err := i.PageStore.RecentChanges(ChangeFilter{
+		Pages: func(title string) bool {
+			return strings.HasPrefix(title, options.Prefix) && allowed(title)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deletedTitles, err := i.PageStore.ListDeletedPages()
+	if err != nil {
+		return nil, err
+	}
+	deleted := make(map[string]bool, len(deletedTitles))
+	for _, title := range deletedTitles {
+		deleted[title] = true
+	}
+
+	found := make(map[string]bool)
+	var results []HistorySearchResult
+	for _, change := range changes {
+		if change.Kind == changeDelete || found[change.Title] {
+			continue
+		}
+
+		body, err := i.PageStore.PageBody(change.Title, change.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		snippet, ok := match(change.Title, string(body))
+		if !ok {
+			continue
+		}
+
+		found[change.Title] = true
+		results = append(results, HistorySearchResult{
+			Title:    change.Title,
+			Revision: change.ID,
+			Date:     change.Date,
+			Deleted:  deleted[change.Title],
+			Snippet:  snippet,
+		})
+		if len(results) == limit {
+			break
+		}
+	}
+	return results, nil
+}
+
+// revisionMatcher returns the function matching a revision of a page against
+// q, or nil if q is empty.
+func revisionMatcher(q string, options SearchOptions) (func(title string, body string) (template.HTML, bool), error) {
+	if options.Mode == searchLiteral || options.Mode == searchRegex {
+		re, err := options.pattern(q)
+		if err != nil {
+			return nil, err
+		}
+		return func(title string, body string) (template.HTML, bool) {
+			result, ok := matchPattern(re, title, body)
+			return result.Snippet, ok
+		}, nil
+	}
+
+	if err := options.check(); err != nil {
+		return nil, err
+	}
+	query, err := parseQuery(q)
+	if err != nil || query == nil {
+		return nil, err
+	}
+	words := query.highlighted()
+
+	return func(title string, body string) (template.HTML, bool) {
+		// Index the revision on its own to match it
+		revision := &SearchIndex{
+			pages:    make(map[string]indexedPage),
+			postings: make(map[string]map[string]*posting),
+		}
+		revision.addPage(title, body)
+		if len(revision.eval(query, false)) == 0 {
+			return "", false
+		}
+		html, _ := snippet(body, words)
+		return html, true
+	}, nil
+}
+
+// refresh rebuilds the index if HEAD moved behind its back, and returns HEAD.
+func (i *SearchIndex) refresh() (string, error) {
+	head, err := i.PageStore.Head()
+	if err != nil {
+		return "", err
+	}
+
+	i.mu.RLock()
+	stale := head != i.head
+	i.mu.RUnlock()
+
+	if stale {
+		if err := i.rebuild(); err != nil {
+			return "", err
+		}
+	}
+	return head, nil
+}
+
+// Search returns the pages matching q, those matching in their title first.
+func (i *SearchIndex) Search(q string, options SearchOptions) ([]PageSearchResult, error) {
+	if err := options.check(); err != nil {
+		return nil, err
+	}
+	if options.Mode == searchLiteral || options.Mode == searchRegex {
+		results, err := i.PageStore.Search(q, options)
+		if err != nil {
+			return nil, err
+		}
+		sortResults(results)
+		return results, nil
+	}
+
+	query, err := parseQuery(q)
+	if err != nil || query == nil {
+		return nil, err
+	}
+
+	head, err := i.refresh()
+	if err != nil {
+		return nil, err
+	}
+
+	words := query.highlighted()
+
+	i.mu.RLock()
+	scores := i.eval(query, false)
+	titleMatches := make(map[string]float64)
+	if len(words) > 0 {
+		titleMatches = i.eval(query, true)
+		excluded := i.excluded(query)
+		for title := range i.pages {
+			if !strings.HasPrefix(title, options.Prefix) || excluded[title] || !query.matchesTitle(strings.ToLower(title)) {
+				continue
+			}
+			titleMatches[title] = 0
+			if _, ok := scores[title]; !ok {
+				scores[title] = 0
+			}
+		}
+	}
+	i.mu.RUnlock()
+
+	results := make([]PageSearchResult, 0, len(scores))
+	for title, score := range scores {
+		if !strings.HasPrefix(title, options.Prefix) {
+			continue
+		}
+		_, titleMatch := titleMatches[title]
+		results = append(results, PageSearchResult{Title: title, Score: score, TitleMatch: titleMatch})
+	}
+	sortResults(results)
+
+	found := results[:0]
+	for _, result := range results {
+		body, err := i.PageStore.PageBody(result.Title, head)
+		if _, ok := err.(*PageNotFound); ok {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Snippet, result.Lines = snippet(string(body), words)
+		found = append(found, result)
+	}
+	return found, nil
+}
+
+// sortResults sorts the pages matching in their title first, then by score.
+func sortResults(results []PageSearchResult) {
+	sort.Slice(results, func(a, b int) bool {
+		if results[a].TitleMatch != results[b].TitleMatch {
+			return results[a].TitleMatch
+		}
+		if results[a].Score != results[b].Score {
+			return results[a].Score > results[b].Score
+		}
+		return results[a].Title < results[b].Title
+	})
+}
+
+// suggestion is a title matching what the user typed. The lower the tier and
+// then the distance, the better the match.
+type suggestion struct {
+	Title    string
+	Tier     int
+	Distance int
+}
+
+// Suggest returns at most limit titles among those allowed the user may be
+// typing as q, best first.
+func (i *SearchIndex) Suggest(q string, allowed func(string) bool, limit int) ([]string, error) {
+	q = strings.ToLower(strings.TrimSpace(q))
+	if q == "" {
+		return nil, nil
+	}
+	if _, err := i.refresh(); err != nil {
+		return nil, err
+	}
+
+	i.mu.RLock()
+	suggestions := make([]suggestion, 0, limit)
+	for title := range i.pages {
+		if s, ok := suggest(title, q); ok && allowed(title) {
+			suggestions = append(suggestions, s)
+		}
+	}
+	i.mu.RUnlock()
+
+	sort.Slice(suggestions, func(a, b int) bool {
+		sa, sb := suggestions[a], suggestions[b]
+		if sa.Tier != sb.Tier {
+			return sa.Tier < sb.Tier
+		}
+		if sa.Distance != sb.Distance {
+			return sa.Distance < sb.Distance
+		}
+		if len(sa.Title) != len(sb.Title) {
+			return len(sa.Title) < len(sb.Title)
+		}
+		return sa.Title < sb.Title
+	})
+
+	titles := make([]string, 0, limit)
+	for n := 0; n < len(suggestions) && n < limit; n++ {
+		titles = append(titles, suggestions[n].Title)
+	}
+	return titles, nil
+}
+
+// suggest reports how well title matches q, in lower case.
+func suggest(title string, q string) (suggestion, bool) {
+	lower := strings.ToLower(title)
+	s := suggestion{Title: title}
+
+	if strings.HasPrefix(lower, q) {
+		return s, true
+	}
+
+	s.Tier++
+	for n, r := range lower {
+		if n > 0 && !unicode.IsLetter(r) && !unicode.IsDigit(r) && strings.HasPrefix(lower[n+utf8.RuneLen(r):], q) {
+			s.Distance = n
+			return s, true
+		}
+	}
+
+	// Letters of q in order, the closer the better
+	s.Tier++
+	if span, ok := subsequenceSpan(lower, q); ok {
+		s.Distance = span
+		return s, true
+	}
+
+	// Typos: one every four letters
+	s.Tier++
+	prefix := []rune(lower)
+	if n := len([]rune(q)); len(prefix) > n {
+		prefix = prefix[:n]
+	}
+	s.Distance = levenshtein(prefix, []rune(q))
+	return s, s.Distance <= len([]rune(q))/4
+}
+
+// subsequenceSpan returns the length of the shortest part of s holding the
+// letters of sub in order.
+func subsequenceSpan(s string, sub string) (int, bool) {
+	runes, subRunes := []rune(s), []rune(sub)
+	best := -1
+	for start := range runes {
+		if runes[start] != subRunes[0] {
+			continue
+		}
+		n := 0
+		end := start
+		for ; end < len(runes) && n < len(subRunes); end++ {
+			if runes[end] == subRunes[n] {
+				n++
+			}
+		}
+		if n < len(subRunes) {
+			break
+		}
+		if best < 0 || end-start < best {
+			best = end - start
+		}
+	}
+	return best, best >= 0
+}
+
+// levenshtein returns the number of runes to insert, delete or substitute to
+// turn a into b.
+func levenshtein(a []rune, b []rune) int {
+	row := make([]int, len(b)+1)
+	for n := range row {
+		row[n] = n
+	}
+	for _, ra := range a {
+		diagonal := row[0]
+		row[0]++
+		for n, rb := range b {
+			cost := 1
+			if ra == rb {
+				cost = 0
+			}
+			above := row[n+1]
+			row[n+1] = min3(row[n+1]+1, row[n]+1, diagonal+cost)
+			diagonal = above
+		}
+	}
+	return row[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+// token is a word of a text, lower cased and stemmed, with its offsets in the
+// text.
+type token struct {
+	Word       string
+	Start, End int
+}
+
+// tokenize splits text into words, made of letters and digits.
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for n, r := range text + " " {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = n
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, token{Word: stem(strings.ToLower(text[start:n])), Start: start, End: n})
+			start = -1
+		}
+	}
+	return tokens
+}
+
+const (
+	queryWord   = "word"
+	queryPhrase = "phrase"
+	queryAnd    = "AND"
+	queryOr     = "OR"
+	queryNot    = "NOT"
+)
+
+// queryNode is a node of a parsed query: a word, a phrase of consecutive
+// words, or an operator applied to its children.
+type queryNode struct {
+	Op       string
+	Words    []string
+	Text     string // word or phrase as typed, in lower case
+	Children []*queryNode
+}
+
+// parseQuery parses a search query of words, "phrases", OR, NOT or - and
+// parentheses.
+func parseQuery(q string) (*queryNode, error) {
+	p := &queryParser{rest: q}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.next() != "" {
+		return nil, &QueryError{"unexpected )"}
+	}
+	return node, nil
+}
+
+// queryParser holds the rest of the query to parse.
+type queryParser struct {
+	rest string
+}
+
+// next returns the next lexeme of the query without consuming it: "(", ")",
+// "-", a quoted phrase, a word, or "" at the end.
+func (p *queryParser) next() string {
+	p.rest = strings.TrimLeftFunc(p.rest, unicode.IsSpace)
+	rest := p.rest
+	if rest == "" {
+		return ""
+	}
+
+	switch rest[0] {
+	case '(', ')', '-':
+		return rest[:1]
+	case '"':
+		if end := strings.IndexByte(rest[1:], '"'); end >= 0 {
+			return rest[:end+2]
+		}
+		return rest
+	}
+	end := strings.IndexFunc(rest, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '(' || r == ')' || r == '"'
+	})
+	if end < 0 {
+		return rest
+	}
+	return rest[:end]
+}
+
+func (p *queryParser) consume(lexeme string) {
+	p.rest = p.rest[len(lexeme):]
+}
+
+func (p *queryParser) parseOr() (*queryNode, error) {
+	var children []*queryNode
+	for {
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			children = append(children, node)
+		}
+		if p.next() != queryOr {
+			break
+		}
+		p.consume(queryOr)
+	}
+	return combine(queryOr, children), nil
+}
+
+func (p *queryParser) parseAnd() (*queryNode, error) {
+	var children []*queryNode
+	for {
+		lexeme := p.next()
+		if lexeme == "" || lexeme == ")" || lexeme == queryOr {
+			break
+		}
+		if lexeme == queryAnd {
+			p.consume(lexeme)
+			continue
+		}
+
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			children = append(children, node)
+		}
+	}
+	return combine(queryAnd, children), nil
+}
+
+func (p *queryParser) parseUnary() (*queryNode, error) {
+	lexeme := p.next()
+	if lexeme == "" || lexeme == ")" || lexeme == queryOr {
+		return nil, nil
+	}
+	p.consume(lexeme)
+
+	switch {
+	case lexeme == queryNot || lexeme == "-":
+		node, err := p.parseUnary()
+		if err != nil || node == nil {
+			return nil, err
+		}
+		return &queryNode{Op: queryNot, Children: []*queryNode{node}}, nil
+
+	case lexeme == "(":
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, &QueryError{"missing )"}
+		}
+		p.consume(")")
+		return node, nil
+
+	case strings.HasPrefix(lexeme, `"`):
+		return newQueryWords(strings.Trim(lexeme, `"`), queryPhrase), nil
+	}
+	return newQueryWords(lexeme, queryWord), nil
+}
+
+// newQueryWords returns the node matching the words of text. Words joined by
+// punctuation, such as "e-mail", make a phrase.
+func newQueryWords(text string, op string) *queryNode {
+	tokens := tokenize(text)
+	if len(tokens) == 0 {
+		return nil
+	}
+	node := &queryNode{Op: op, Text: strings.ToLower(text)}
+	if len(tokens) > 1 {
+		node.Op = queryPhrase
+	}
+	for _, token := range tokens {
+		node.Words = append(node.Words, token.Word)
+	}
+	return node
+}
+
+func combine(op string, children []*queryNode) *queryNode {
+	switch len(children) {
+	case 0:
+		return nil
+	case 1:
+		return children[0]
+	}
+	return &queryNode{Op: op, Children: children}
+}
+
+// highlighted returns the words of the pages matching the query, that is the
+// words it contains but not under a NOT.
+func (n *queryNode) highlighted() map[string]bool {
+	words := make(map[string]bool)
+	var walk func(*queryNode)
+	walk = func(n *queryNode) {
+		if n.Op == queryNot {
+			return
+		}
+		for _, word := range n.Words {
+			words[word] = true
+		}
+		for _, child := range n.Children {
+			walk(child)
+		}
+	}
+	walk(n)
+	return words
+}
+
+// matchesTitle reports whether title, in lower case, holds the words and
+// phrases of the query as typed, such as part of a word or a path.
+func (n *queryNode) matchesTitle(title string) bool {
+	switch n.Op {
+	case queryAnd:
+		for _, child := range n.Children {
+			if !child.matchesTitle(title) {
+				return false
+			}
+		}
+		return true
+	case queryOr:
+		for _, child := range n.Children {
+			if child.matchesTitle(title) {
+				return true
+			}
+		}
+		return false
+	case queryNot:
+		return !n.Children[0].matchesTitle(title)
+	}
+	return strings.Contains(title, n.Text)
+}
+
+// excluded returns the pages matching what the query excludes. The caller
+// must hold the read lock.
+func (i *SearchIndex) excluded(n *queryNode) map[string]bool {
+	excluded := make(map[string]bool)
+	var walk func(*queryNode)
+	walk = func(n *queryNode) {
+		if n.Op == queryNot {
+			for title := range i.eval(n.Children[0], false) {
+				excluded[title] = true
+			}
+			return
+		}
+		for _, child := range n.Children {
+			walk(child)
+		}
+	}
+	walk(n)
+	return excluded
+}
+
+// eval returns the pages matching n, in their title only if titleOnly is set,
+// with their BM25 score. The caller must hold the read lock.
+func (i *SearchIndex) eval(n *queryNode, titleOnly bool) map[string]float64 {
+	scores := make(map[string]float64)
+
+	switch n.Op {
+	case queryWord, queryPhrase:
+		counts := i.matches(n.Words, titleOnly)
+		if len(counts) == 0 {
+			return scores
+		}
+		idf := math.Log(1 + (float64(len(i.pages))-float64(len(counts))+0.5)/(float64(len(counts))+0.5))
+		avgLength := float64(i.totalLength) / float64(len(i.pages))
+		if avgLength == 0 {
+			avgLength = 1
+		}
+		for title, tf := range counts {
+			norm := 1 - searchB + searchB*float64(i.pages[title].Length)/avgLength
+			scores[title] = idf * tf * (searchK1 + 1) / (tf + searchK1*norm)
+		}
+
+	case queryAnd:
+		var excluded []map[string]float64
+		first := true
+		for _, child := range n.Children {
+			if child.Op == queryNot {
+				excluded = append(excluded, i.eval(child.Children[0], titleOnly))
+				continue
+			}
+			matches := i.eval(child, titleOnly)
+			if first {
+				scores, first = matches, false
+				continue
+			}
+			for title, score := range scores {
+				if match, ok := matches[title]; ok {
+					scores[title] = score + match
+				} else {
+					delete(scores, title)
+				}
+			}
+		}
+		if first {
+			// Only exclusions: start from all the pages
+			for title := range i.pages {
+				scores[title] = 0
+			}
+		}
+		for _, matches := range excluded {
+			for title := range matches {
+				delete(scores, title)
+			}
+		}
+
+	case queryOr:
+		for _, child := range n.Children {
+			for title, score := range i.eval(child, titleOnly) {
+				scores[title] += score
+			}
+		}
+
+	case queryNot:
+		excluded := i.eval(n.Children[0], titleOnly)
+		for title := range i.pages {
+			if _, ok := excluded[title]; !ok {
+				scores[title] = 0
+			}
+		}
+	}
+	return scores
+}
+
+// matches returns the weighted number of occurrences of the phrase words in
+// each page. The caller must hold the read lock.
+func (i *SearchIndex) matches(words []string, titleOnly bool) map[string]float64 {
+	counts := make(map[string]float64)
+	for title, first := range i.postings[words[0]] {
+		rest := make([]*posting, 0, len(words)-1)
+		for _, word := range words[1:] {
+			p := i.postings[word][title]
+			if p == nil {
+				break
+			}
+			rest = append(rest, p)
+		}
+		if len(rest) < len(words)-1 {
+			continue
+		}
+
+		tf := 0.0
+		if !titleOnly {
+			tf = float64(phraseCount(first.Body, rest, func(p *posting) []int { return p.Body }))
+		}
+		tf += searchTitleWeight * float64(phraseCount(first.Title, rest, func(p *posting) []int { return p.Title }))
+		if tf > 0 {
+			counts[title] = tf
+		}
+	}
+	return counts
+}
+
+// phraseCount returns the number of positions of the first word followed by
+// the other words, in order.
+func phraseCount(positions []int, rest []*posting, field func(*posting) []int) int {
+	count := 0
+	for _, start := range positions {
+		found := true
+		for offset, p := range rest {
+			if !containsInt(field(p), start+offset+1) {
+				found = false
+				break
+			}
+		}
+		if found {
+			count++
+		}
+	}
+	return count
+}
+
+// containsInt reports whether the sorted values hold v.
+func containsInt(values []int, v int) bool {
+	n := sort.SearchInts(values, v)
+	return n < len(values) && values[n] == v
+}
+
+// snippet returns the passage of body with the most words to highlight, with
+// these words marked, and the lines holding them.
+func snippet(body string, words map[string]bool) (template.HTML, []string) {
+	tokens := tokenize(body)
+	if len(tokens) == 0 {
+		return "", nil
+	}
+
+	// Slide a window of snippetWords words to find the most matches
+	best, bestCount, count := 0, 0, 0
+	for n := range tokens {
+		if words[tokens[n].Word] {
+			count++
+		}
+		if n >= snippetWords && words[tokens[n-snippetWords].Word] {
+			count--
+		}
+		if count > bestCount {
+			best, bestCount = n-snippetWords+1, count
+		}
+	}
+	if best < 0 {
+		best = 0
+	}
+	last := best + snippetWords - 1
+	if last >= len(tokens) {
+		last = len(tokens) - 1
+	}
+
+	var html strings.Builder
+	if best > 0 {
+		html.WriteString("… ")
+	}
+	pos := tokens[best].Start
+	for _, token := range tokens[best : last+1] {
+		if !words[token.Word] {
+			continue
+		}
+		html.WriteString(template.HTMLEscapeString(body[pos:token.Start]))
+		html.WriteString("<mark>" + template.HTMLEscapeString(body[token.Start:token.End]) + "</mark>")
+		pos = token.End
+	}
+	if last < len(tokens)-1 {
+		html.WriteString(template.HTMLEscapeString(body[pos:tokens[last].End]) + " …")
+	} else {
+		html.WriteString(template.HTMLEscapeString(body[pos:]))
+	}
+
+	var lines []string
+	for _, line := range splitLines(body) {
+		if len(lines) == snippetLines {
+			break
+		}
+		for _, token := range tokenize(line) {
+			if words[token.Word] {
+				lines = append(lines, strings.TrimSpace(line))
+				break
+			}
+		}
+	}
+
+	return template.HTML(strings.Join(strings.Fields(html.String()), " ")), lines
+}
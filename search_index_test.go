@@ -0,0 +1,105 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSearchIndexCommitBehindItsBack(t *testing.T) {
+	storage := NewMemoryStorage()
+	index, err := NewSearchIndex(storage, filepath.Join(t.TempDir(), "search-index.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer index.Close()
+
+	if _, _, err := storage.SetPageBody("A", "apple\n", "", "", Author{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := index.SetPageBody("B", "banana\n", "", "", Author{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, q := range []string{"apple", "banana"} {
+		results, err := index.Search(q, SearchOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 1 {
+			t.Errorf("Search(%q) = %d results, want 1", q, len(results))
+		}
+	}
+}
+
+func TestSearchIndexClose(t *testing.T) {
+	storage := NewMemoryStorage()
+	filename := filepath.Join(t.TempDir(), "search-index.json")
+	index, err := NewSearchIndex(storage, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := index.SetPageBody("A", "apple\n", "", "", Author{}); err != nil {
+		t.Fatal(err)
+	}
+	index.Close()
+
+	saved := &SearchIndex{filename: filename}
+	if err := saved.load(); err != nil {
+		t.Fatal(err)
+	}
+	head, _ := storage.Head()
+	if saved.head != head || len(saved.pages) != 1 {
+		t.Errorf("Saved index at %s with %d pages, want %s with 1 page", saved.head, len(saved.pages), head)
+	}
+}
+
+func TestSearchTitles(t *testing.T) {
+	storage := NewMemoryStorage()
+	pages := map[string]string{
+		"Wiki/Setup": "how to install\n",
+		"Draft wiki": "unfinished\n",
+		"Wikipedia":  "encyclopedia\n",
+		"Notes":      "wiki draft copy\n",
+		"Old-draft":  "stale\n",
+		"Foo":        "bar\n",
+	}
+	for title, body := range pages {
+		if _, _, err := storage.SetPageBody(title, body, "", "", Author{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	index, err := NewSearchIndex(storage, filepath.Join(t.TempDir(), "search-index.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer index.Close()
+
+	// Title of each page found -> whether it matches in its title
+	tests := []struct {
+		q    string
+		want map[string]bool
+	}{
+		{"wiki", map[string]bool{"Wiki/Setup": true, "Draft wiki": true, "Wikipedia": true, "Notes": false}},
+		{"wiki -draft", map[string]bool{"Wiki/Setup": true, "Wikipedia": true}},
+		{"pedia OR setup", map[string]bool{"Wiki/Setup": true, "Wikipedia": true}},
+		{"-draft", map[string]bool{"Wiki/Setup": false, "Wikipedia": false, "Foo": false}},
+		{"old-draft", map[string]bool{"Old-draft": true}},
+		{"wiki -install", map[string]bool{"Draft wiki": true, "Wikipedia": true, "Notes": false}},
+		{"wiki NOT copy", map[string]bool{"Wiki/Setup": true, "Draft wiki": true, "Wikipedia": true}},
+	}
+
+	for _, test := range tests {
+		results, err := index.Search(test.q, SearchOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := make(map[string]bool)
+		for _, result := range results {
+			found[result.Title] = result.TitleMatch
+		}
+		if !reflect.DeepEqual(found, test.want) {
+			t.Errorf("Search(%q) = %v, want %v", test.q, found, test.want)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+// stem returns the stem of the lower case word using the Porter algorithm.
+func stem(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return word
+		}
+	}
+
+	p := &porter{b: []byte(word), k: len(word) - 1}
+	p.step1ab()
+	if p.k > 0 {
+		p.step1c()
+		p.step2()
+		p.step3()
+		p.step4()
+		p.step5()
+	}
+	return string(p.b[:p.k+1])
+}
+
+// porter holds a word being stemmed in b[0:k+1]. j marks the end of the stem
+// left by the last suffix matched.
+type porter struct {
+	b    []byte
+	k, j int
+}
+
+// cons reports whether b[i] is a consonant.
+func (p *porter) cons(i int) bool {
+	switch p.b[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		return i == 0 || !p.cons(i-1)
+	}
+	return true
+}
+
+// m returns the number of vowel-consonant sequences in b[0:j+1].
+func (p *porter) m() int {
+	n, i := 0, 0
+	for ; i <= p.j && p.cons(i); i++ {
+	}
+	for i <= p.j {
+		for ; i <= p.j && !p.cons(i); i++ {
+		}
+		if i > p.j {
+			break
+		}
+		n++
+		for ; i <= p.j && p.cons(i); i++ {
+		}
+	}
+	return n
+}
+
+func (p *porter) vowelInStem() bool {
+	for i := 0; i <= p.j; i++ {
+		if !p.cons(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// doublec reports whether b[i-1:i+1] is a double consonant.
+func (p *porter) doublec(i int) bool {
+	return i >= 1 && p.b[i] == p.b[i-1] && p.cons(i)
+}
+
+// cvc reports whether b[i-2:i+1] is consonant-vowel-consonant with the last
+// consonant not w, x or y, as in "hop" but not "snow".
+func (p *porter) cvc(i int) bool {
+	if i < 2 || !p.cons(i) || p.cons(i-1) || !p.cons(i-2) {
+		return false
+	}
+	switch p.b[i] {
+	case 'w', 'x', 'y':
+		return false
+	}
+	return true
+}
+
+func (p *porter) ends(s string) bool {
+	if len(s) > p.k+1 || string(p.b[p.k+1-len(s):p.k+1]) != s {
+		return false
+	}
+	p.j = p.k - len(s)
+	return true
+}
+
+func (p *porter) setTo(s string) {
+	p.b = append(p.b[:p.j+1], s...)
+	p.k = p.j + len(s)
+}
+
+func (p *porter) replace(s string) {
+	if p.m() > 0 {
+		p.setTo(s)
+	}
+}
+
+// step1ab removes plurals and -ed or -ing.
+func (p *porter) step1ab() {
+	if p.b[p.k] == 's' {
+		switch {
+		case p.ends("sses"):
+			p.k -= 2
+		case p.ends("ies"):
+			p.setTo("i")
+		case p.b[p.k-1] != 's':
+			p.k--
+		}
+	}
+
+	if p.ends("eed") {
+		if p.m() > 0 {
+			p.k--
+		}
+	} else if (p.ends("ed") || p.ends("ing")) && p.vowelInStem() {
+		p.k = p.j
+		switch {
+		case p.ends("at"):
+			p.setTo("ate")
+		case p.ends("bl"):
+			p.setTo("ble")
+		case p.ends("iz"):
+			p.setTo("ize")
+		case p.doublec(p.k):
+			if c := p.b[p.k]; c != 'l' && c != 's' && c != 'z' {
+				p.k--
+			}
+		case p.m() == 1 && p.cvc(p.k):
+			p.setTo("e")
+		}
+	}
+}
+
+// step1c turns a terminal y into i when there is another vowel in the stem.
+func (p *porter) step1c() {
+	if p.ends("y") && p.vowelInStem() {
+		p.b[p.k] = 'i'
+	}
+}
+
+// replaceSuffix replaces the first of the suffixes matching, given as pairs
+// of a suffix and its replacement.
+func (p *porter) replaceSuffix(suffixes [][2]string) {
+	for _, suffix := range suffixes {
+		if p.ends(suffix[0]) {
+			p.replace(suffix[1])
+			return
+		}
+	}
+}
+
+// step2Suffixes maps double suffixes to single ones, by the letter before
+// last.
+var step2Suffixes = map[byte][][2]string{
+	'a': {{"ational", "ate"}, {"tional", "tion"}},
+	'c': {{"enci", "ence"}, {"anci", "ance"}},
+	'e': {{"izer", "ize"}},
+	'l': {{"bli", "ble"}, {"alli", "al"}, {"entli", "ent"}, {"eli", "e"}, {"ousli", "ous"}},
+	'o': {{"ization", "ize"}, {"ation", "ate"}, {"ator", "ate"}},
+	's': {{"alism", "al"}, {"iveness", "ive"}, {"fulness", "ful"}, {"ousness", "ous"}},
+	't': {{"aliti", "al"}, {"iviti", "ive"}, {"biliti", "ble"}},
+	'g': {{"logi", "log"}},
+}
+
+func (p *porter) step2() {
+	p.replaceSuffix(step2Suffixes[p.b[p.k-1]])
+}
+
+// step3Suffixes deals with -ic-, -full, -ness etc., by the last letter.
+var step3Suffixes = map[byte][][2]string{
+	'e': {{"icate", "ic"}, {"ative", ""}, {"alize", "al"}},
+	'i': {{"iciti", "ic"}},
+	'l': {{"ical", "ic"}, {"ful", ""}},
+	's': {{"ness", ""}},
+}
+
+func (p *porter) step3() {
+	p.replaceSuffix(step3Suffixes[p.b[p.k]])
+}
+
+// step4Suffixes are removed in context m() > 1, by the letter before last.
+var step4Suffixes = map[byte][]string{
+	'a': {"al"},
+	'c': {"ance", "ence"},
+	'e': {"er"},
+	'i': {"ic"},
+	'l': {"able", "ible"},
+	'n': {"ant", "ement", "ment", "ent"},
+	'o': {"ion", "ou"},
+	's': {"ism"},
+	't': {"ate", "iti"},
+	'u': {"ous"},
+	'v': {"ive"},
+	'z': {"ize"},
+}
+
+func (p *porter) step4() {
+	for _, suffix := range step4Suffixes[p.b[p.k-1]] {
+		if !p.ends(suffix) {
+			continue
+		}
+		// -ion is only removed after s or t
+		if suffix == "ion" && (p.j < 0 || (p.b[p.j] != 's' && p.b[p.j] != 't')) {
+			continue
+		}
+		if p.m() > 1 {
+			p.k = p.j
+		}
+		return
+	}
+}
+
+// step5 removes a final -e and turns -ll into -l when m() > 1.
+func (p *porter) step5() {
+	p.j = p.k
+	if p.b[p.k] == 'e' {
+		if m := p.m(); m > 1 || (m == 1 && !p.cvc(p.k-1)) {
+			p.k--
+		}
+	}
+	if p.b[p.k] == 'l' && p.doublec(p.k) && p.m() > 1 {
+		p.k--
+	}
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"html/template"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// PageStore is the interface implemented by the page storage backends. Writes
+// return the revision they created, which HEAD may no longer be.
+type PageStore interface {
+	Attachment(title string, name string, revision string) ([]byte, error)
+	Attachments(title string, revision string) ([]string, error)
+	DeleteAttachment(title string, name string, message string, author Author) error
+	DeletePage(title string, author Author) (string, error)
+	Diff(title string, body string) (*PageDiff, error)
+	DiffPages(fromTitle string, from string, toTitle string, to string) (*PageDiff, error)
+	DiffRevisions(title string, from string, to string) (*PageDiff, error)
+	Head() (string, error)
+	History(title string) ([]Commit, error)
+	ListDeletedPages() ([]string, error)
+	ListPages() ([]string, error)
+	PageBody(title string, revision string) ([]byte, error)
+	Parent(revision string) (string, error)
+	RecentChanges(filter ChangeFilter) ([]Change, error)
+	RenamePage(title string, newTitle string, message string, redirect bool, updateLinks bool, author Author) (string, error)
+	Search(q string, options SearchOptions) ([]PageSearchResult, error)
+	SetAttachment(title string, name string, content []byte, message string, author Author) error
+	SetPageBody(title string, body string, message string, baseRevision string, author Author) (string, bool, error)
+}
+
+// Author identifies who makes a change. The zero Author stands for the
+// identity of the storage itself.
+type Author struct {
+	Name  string
+	Email string
+}
+
+const (
+	changeAdd    = "add"
+	changeModify = "modify"
+	changeDelete = "delete"
+)
+
+// Change is a page added, modified or deleted by a commit. Parent is the
+// revision the commit was made on, empty for the first one.
+type Change struct {
+	Commit
+	Kind   string
+	Parent string
+}
+
+// ChangeFilter selects recent changes. Zero fields select everything.
+type ChangeFilter struct {
+	Since  time.Time
+	Until  time.Time
+	Author string // name or email
+	Pages  func(title string) bool
+	Offset int
+	Limit  int
+}
+
+// matchCommit reports whether the commits made by author at date are
+// selected. done reports whether older commits cannot be selected either.
+func (f ChangeFilter) matchCommit(date time.Time, author Author) (match bool, done bool) {
+	if !f.Since.IsZero() && date.Before(f.Since) {
+		return false, true
+	}
+	if !f.Until.IsZero() && !date.Before(f.Until) {
+		return false, false
+	}
+	return f.Author == "" || f.Author == author.Name || f.Author == author.Email, false
+}
+
+// changeCollector gathers the changes selected by filter, in order.
+type changeCollector struct {
+	filter  ChangeFilter
+	skipped int
+	changes []Change
+}
+
+func newChangeCollector(filter ChangeFilter) *changeCollector {
+	return &changeCollector{filter: filter, changes: make([]Change, 0)}
+}
+
+// add records change if selected. It reports whether the limit of the filter
+// is reached.
+func (c *changeCollector) add(change Change) bool {
+	if c.filter.Pages != nil && !c.filter.Pages(change.Title) {
+		return false
+	}
+	if c.skipped < c.filter.Offset {
+		c.skipped++
+		return false
+	}
+	c.changes = append(c.changes, change)
+	return c.filter.Limit > 0 && len(c.changes) >= c.filter.Limit
+}
+
+// PageNotFound is returned when a page does not exist, at least at the
+// revision asked for.
+type PageNotFound struct {
+	Title string
+}
+
+func (e *PageNotFound) Error() string {
+	return "Page not found: " + e.Title
+}
+
+// QueryError reports a search query which cannot be parsed, or options which
+// cannot be combined.
+type QueryError struct {
+	Message string
+}
+
+func (e *QueryError) Error() string {
+	return "Invalid query: " + e.Message
+}
+
+const (
+	searchWords   = "words"
+	searchLiteral = "literal"
+	searchRegex   = "regex"
+)
+
+// SearchOptions selects how the query of a search is matched and which pages
+// are searched.
+type SearchOptions struct {
+	Mode          string
+	CaseSensitive bool
+	WholeWord     bool
+	Prefix        string // searches the pages whose title starts with it
+}
+
+// check reports options which cannot be used.
+func (o SearchOptions) check() error {
+	switch o.Mode {
+	case "", searchWords:
+		if o.CaseSensitive || o.WholeWord {
+			return &QueryError{"case-sensitive and whole-word searches need a literal or regex search"}
+		}
+	case searchLiteral, searchRegex:
+	default:
+		return &QueryError{"unknown search mode " + o.Mode}
+	}
+	return nil
+}
+
+// pattern compiles the query q of a literal or regex search.
+func (o SearchOptions) pattern(q string) (*regexp.Regexp, error) {
+	if err := o.check(); err != nil {
+		return nil, err
+	}
+	if q == "" {
+		return nil, &QueryError{"empty query"}
+	}
+
+	expr := regexp.QuoteMeta(q)
+	if o.Mode == searchRegex {
+		// Reported on its own, the errors point at the query as typed
+		if _, err := regexp.Compile(q); err != nil {
+			return nil, &QueryError{strings.TrimPrefix(err.Error(), "error parsing regexp: ")}
+		}
+		expr = q
+	}
+	if o.WholeWord {
+		expr = `\b(?:` + expr + `)\b`
+	}
+	if !o.CaseSensitive {
+		expr = "(?i)" + expr
+	}
+	return regexp.Compile(expr)
+}
+
+// matchPattern returns the result of the page title with body for a literal
+// or regex search, and whether it matches.
+func matchPattern(re *regexp.Regexp, title string, body string) (PageSearchResult, bool) {
+	result := PageSearchResult{Title: title, TitleMatch: re.MatchString(title)}
+
+	var snippet []string
+	for _, line := range splitLines(body) {
+		matches := re.FindAllStringIndex(line, -1)
+		if len(matches) == 0 {
+			continue
+		}
+		result.Lines = append(result.Lines, line)
+		result.Score += float64(len(matches))
+
+		if len(snippet) == snippetLines {
+			continue
+		}
+		var html strings.Builder
+		pos := 0
+		for _, match := range matches {
+			if match[0] == match[1] {
+				continue
+			}
+			html.WriteString(template.HTMLEscapeString(line[pos:match[0]]))
+			html.WriteString("<mark>" + template.HTMLEscapeString(line[match[0]:match[1]]) + "</mark>")
+			pos = match[1]
+		}
+		html.WriteString(template.HTMLEscapeString(line[pos:]))
+		snippet = append(snippet, html.String())
+	}
+	result.Snippet = template.HTML(strings.Join(snippet, "<br>"))
+
+	return result, result.TitleMatch || len(result.Lines) > 0
+}
+
+// validAttachmentName reports whether name can be used as the file name of an
+// attachment.
+func validAttachmentName(name string) bool {
+	return name != "" && !strings.HasPrefix(name, ".") && !strings.ContainsAny(name, "/\\\x00")
+}
+
+// titleAt returns the title the page with the given history had at revision.
+// Revisions missing from the history fall back to its current title.
+func titleAt(history []Commit, title string, revision string) string {
+	if revision == "" {
+		return title
+	}
+	for _, commit := range history {
+		if strings.HasPrefix(commit.ID, revision) && commit.Title != "" {
+			return commit.Title
+		}
+	}
+	return title
+}
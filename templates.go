@@ -7,12 +7,36 @@ import (
 
 var (
 	templateFilenames = map[string][]string{
+		"acl": []string{
+			"_base.html",
+			"_head.html",
+			"_body.html",
+			"acl.html",
+		},
 		"allPages": []string{
 			"_base.html",
 			"_head.html",
 			"_body.html",
 			"all-pages.html",
 		},
+		"attachments": []string{
+			"_base.html",
+			"_head.html",
+			"_body.html",
+			"attachments.html",
+		},
+		"backlinks": []string{
+			"_base.html",
+			"_head.html",
+			"_body.html",
+			"backlinks.html",
+		},
+		"compare": []string{
+			"_base.html",
+			"_head.html",
+			"_body.html",
+			"compare.html",
+		},
 		"deleted": []string{
 			"_base.html",
 			"_head.html",
@@ -41,6 +65,12 @@ var (
 			"_body.html",
 			"history.html",
 		},
+		"login": []string{
+			"_base.html",
+			"_head.html",
+			"_body.html",
+			"login.html",
+		},
 		"printable": []string{
 			"printable.html",
 		},
@@ -52,6 +82,18 @@ var (
 			"_edit.html",
 			"preview.html",
 		},
+		"recentChanges": []string{
+			"_base.html",
+			"_head.html",
+			"_body.html",
+			"recent-changes.html",
+		},
+		"rename": []string{
+			"_base.html",
+			"_head.html",
+			"_body.html",
+			"rename.html",
+		},
 		"search": []string{
 			"_base.html",
 			"_head.html",
@@ -65,6 +107,18 @@ var (
 			"_delete.html",
 			"view.html",
 		},
+		"worktree": []string{
+			"_base.html",
+			"_head.html",
+			"_body.html",
+			"worktree.html",
+		},
+		"webhooks": []string{
+			"_base.html",
+			"_head.html",
+			"_body.html",
+			"webhooks.html",
+		},
 	}
 )
 
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	webhookSave   = "save"
+	webhookDelete = "delete"
+	webhookRename = "rename"
+
+	// Failed deliveries are retried after webhookRetryDelay, then after
+	// delays growing 4 times each time
+	webhookAttempts   = 5
+	webhookRetryDelay = 2 * time.Second
+	webhookTimeout    = 10 * time.Second
+	webhookLogSize    = 100
+
+	webhookEventHeader     = "X-Wiki-Event"
+	webhookDeliveryHeader  = "X-Wiki-Delivery"
+	webhookSignatureHeader = "X-Wiki-Signature"
+)
+
+// WebhookEndpoint is a URL notified of the changes made to the pages. Events
+// restricts the notifications to some actions, all of them when empty.
+type WebhookEndpoint struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events,omitempty"`
+}
+
+func (e WebhookEndpoint) wants(action string) bool {
+	if len(e.Events) == 0 {
+		return true
+	}
+	for _, event := range e.Events {
+		if event == action {
+			return true
+		}
+	}
+	return false
+}
+
+type webhookAuthor struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// WebhookPayload is the JSON body posted to the endpoints. Changes made
+// anonymously have no author.
+type WebhookPayload struct {
+	Action   string         `json:"action"`
+	Title    string         `json:"title"`
+	OldTitle string         `json:"old_title,omitempty"`
+	Revision string         `json:"revision"`
+	Author   *webhookAuthor `json:"author,omitempty"`
+	Message  string         `json:"message"`
+	Time     time.Time      `json:"time"`
+}
+
+func newWebhookAuthor(author Author) *webhookAuthor {
+	if author == (Author{}) {
+		return nil
+	}
+	return &webhookAuthor{Name: author.Name, Email: author.Email}
+}
+
+// WebhookDelivery is the state of the notification of an endpoint.
+type WebhookDelivery struct {
+	ID        string
+	URL       string
+	Action    string
+	Title     string
+	Time      time.Time
+	Attempts  int
+	Status    int
+	Error     string
+	Delivered bool
+}
+
+// Webhooks is a PageStore posting a signed JSON payload to the configured
+// endpoints after each write made through it.
+type Webhooks struct {
+	PageStore
+
+	filename string
+	client   *http.Client
+
+	mu         sync.Mutex
+	deliveries []*WebhookDelivery
+}
+
+func NewWebhooks(store PageStore, filename string) *Webhooks {
+	return &Webhooks{
+		PageStore: store,
+		filename:  filename,
+		client:    &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+func parseWebhooks(content []byte) ([]WebhookEndpoint, error) {
+	var endpoints []WebhookEndpoint
+	if err := json.Unmarshal(content, &endpoints); err != nil {
+		return nil, errors.New("Invalid webhooks: " + err.Error())
+	}
+
+	for _, endpoint := range endpoints {
+		u, err := url.Parse(endpoint.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, errors.New("Invalid webhook URL: " + endpoint.URL)
+		}
+		for _, event := range endpoint.Events {
+			if event != webhookSave && event != webhookDelete && event != webhookRename {
+				return nil, errors.New("Invalid webhook event: " + event)
+			}
+		}
+	}
+	return endpoints, nil
+}
+
+// Endpoints returns the configured endpoints.
+func (h *Webhooks) Endpoints() ([]WebhookEndpoint, error) {
+	content, err := ioutil.ReadFile(h.filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseWebhooks(content)
+}
+
+// Source returns the content of the webhooks file.
+func (h *Webhooks) Source() (string, error) {
+	content, err := ioutil.ReadFile(h.filename)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	return string(content), err
+}
+
+// SetSource replaces the webhooks file with content once validated.
+func (h *Webhooks) SetSource(content string) error {
+	if _, err := parseWebhooks([]byte(content)); err != nil {
+		return err
+	}
+	return writeFileAtomic(h.filename, []byte(content), 0600)
+}
+
+// Deliveries returns the latest deliveries, most recent first.
+func (h *Webhooks) Deliveries() []WebhookDelivery {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	deliveries := make([]WebhookDelivery, len(h.deliveries))
+	for i, d := range h.deliveries {
+		deliveries[len(h.deliveries)-1-i] = *d
+	}
+	return deliveries
+}
+
+// notify sends payload to the endpoints interested in its action.
+func (h *Webhooks) notify(payload WebhookPayload) {
+	endpoints, err := h.Endpoints()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if len(endpoints) == 0 {
+		return
+	}
+
+	payload.Time = time.Now().UTC()
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	for _, endpoint := range endpoints {
+		if !endpoint.wants(payload.Action) {
+			continue
+		}
+
+		id := make([]byte, 16)
+		if _, err := rand.Read(id); err != nil {
+			log.Println(err)
+			return
+		}
+		d := &WebhookDelivery{
+			ID:     hex.EncodeToString(id),
+			URL:    endpoint.URL,
+			Action: payload.Action,
+			Title:  payload.Title,
+			Time:   payload.Time,
+		}
+
+		h.mu.Lock()
+		h.deliveries = append(h.deliveries, d)
+		if len(h.deliveries) > webhookLogSize {
+			h.deliveries = h.deliveries[len(h.deliveries)-webhookLogSize:]
+		}
+		h.mu.Unlock()
+
+		go h.deliver(endpoint, d, body)
+	}
+}
+
+// deliver posts body to endpoint until it is accepted or the attempts run
+// out.
+func (h *Webhooks) deliver(endpoint WebhookEndpoint, d *WebhookDelivery, body []byte) {
+	delay := webhookRetryDelay
+	for attempt := 1; ; attempt++ {
+		status, err := h.post(endpoint, d, body)
+		if err == nil && (status < 200 || status > 299) {
+			err = errors.New("Unexpected status " + strconv.Itoa(status))
+		}
+
+		h.mu.Lock()
+		d.Attempts, d.Status, d.Delivered, d.Error = attempt, status, err == nil, ""
+		if err != nil {
+			d.Error = err.Error()
+		}
+		h.mu.Unlock()
+
+		if err == nil {
+			return
+		}
+		if attempt == webhookAttempts {
+			log.Println("Webhook delivery " + d.ID + " to " + d.URL + " failed: " + err.Error())
+			return
+		}
+		time.Sleep(delay)
+		delay *= 4
+	}
+}
+
+func (h *Webhooks) post(endpoint WebhookEndpoint, d *WebhookDelivery, body []byte) (int, error) {
+	req, err := http.NewRequest("POST", endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	mac := hmac.New(sha256.New, []byte(endpoint.Secret))
+	mac.Write(body)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "wiki")
+	req.Header.Set(webhookEventHeader, d.Action)
+	req.Header.Set(webhookDeliveryHeader, d.ID)
+	req.Header.Set(webhookSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	return resp.StatusCode, nil
+}
+
+func (h *Webhooks) DeletePage(title string, author Author) (string, error) {
+	revision, err := h.PageStore.DeletePage(title, author)
+	if err != nil {
+		return "", err
+	}
+	h.notify(WebhookPayload{
+		Action:   webhookDelete,
+		Title:    title,
+		Revision: revision,
+		Author:   newWebhookAuthor(author),
+		Message:  "Delete " + title,
+	})
+	return revision, nil
+}
+
+func (h *Webhooks) RenamePage(title string, newTitle string, message string, redirect bool, updateLinks bool, author Author) (string, error) {
+	revision, err := h.PageStore.RenamePage(title, newTitle, message, redirect, updateLinks, author)
+	if err != nil {
+		return "", err
+	}
+	h.notify(WebhookPayload{
+		Action:   webhookRename,
+		Title:    newTitle,
+		Revision: revision,
+		OldTitle: title,
+		Author:   newWebhookAuthor(author),
+		Message:  message,
+	})
+	return revision, nil
+}
+
+func (h *Webhooks) SetPageBody(title string, body string, message string, baseRevision string, author Author) (string, bool, error) {
+	revision, created, err := h.PageStore.SetPageBody(title, body, message, baseRevision, author)
+	if err != nil {
+		return "", false, err
+	}
+	h.notify(WebhookPayload{
+		Action:   webhookSave,
+		Title:    title,
+		Revision: revision,
+		Author:   newWebhookAuthor(author),
+		Message:  message,
+	})
+	return revision, created, nil
+}
@@ -1,10 +1,18 @@
 package main
 
 import (
+	"bufio"
+	"errors"
 	"flag"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
 	"strings"
+	"syscall"
 
 	"github.com/gorilla/mux"
 )
@@ -12,6 +20,22 @@ import (
 const (
 	pagesDir      = "pages"
 	pageExtension = ".md"
+
+	attachmentsDir    = "attachments"
+	maxAttachmentSize = 32 << 20
+
+	recentChangesPerPage = 50
+	dateFormat           = "2006-01-02"
+
+	// suggestSize is the number of titles suggested as the user types.
+	suggestSize = 10
+
+	// historySearchSize is the number of pages found by a search in the history
+	historySearchSize = 100
+
+	// stateDir holds the files of the wiki itself, such as the accounts, in
+	// the data directory. It is not part of the git repository.
+	stateDir = ".wiki"
 )
 
 func bodyAction(action string) func(r *http.Request, rm *mux.RouteMatch) bool {
@@ -24,27 +48,45 @@ func pageNameMatcher(r *http.Request, rm *mux.RouteMatch) bool {
 	return r.URL.Path != "/_" && !strings.HasPrefix(r.URL.Path, "/_/")
 }
 
-func main() {
-	var addr string
-	var dataDir string
-	flag.StringVar(&addr, "addr", "127.0.0.1:8000", "TCP address to listen on")
-	flag.StringVar(&dataDir, "data-dir", "data", "Data directory")
-	flag.Parse()
+// addUser creates or updates the local account of args, reading its password
+// from the standard input.
+func addUser(users *UserStore, args []string) error {
+	if len(args) == 0 {
+		return errors.New("Usage: wiki [options] adduser NAME [EMAIL [GROUP...]]")
+	}
 
-	storage := NewGitStorage(dataDir, pagesDir, pageExtension)
-	if err := storage.Init(); err != nil {
-		log.Fatal(err)
+	user := User{Name: args[0]}
+	if len(args) > 1 {
+		user.Email = args[1]
+		user.Groups = args[2:]
 	}
 
-	templates, err := NewTemplates()
-	if err != nil {
-		log.Fatal(err)
+	fmt.Fprint(os.Stderr, "Password: ")
+	password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return err
 	}
-	app := AppContext{
-		Storage:   storage,
-		templates: templates,
+	password = strings.TrimRight(password, "\r\n")
+	if password == "" {
+		return errors.New("Empty password")
+	}
+
+	return users.SetUser(user, password)
+}
+
+// splitList returns the non-empty items of the comma separated list s.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
 	}
+	return items
+}
 
+// handler routes the requests to the handlers of app.
+func (app AppContext) handler() http.Handler {
 	router := mux.NewRouter()
 	router.StrictSlash(true)
 
@@ -55,9 +97,22 @@ func main() {
 		// Delete
 		router.HandleFunc(path, app.deleteHandler).MatcherFunc(pageNameMatcher).Queries("action", "delete").Methods("POST")
 
+		// Attachments
+		router.HandleFunc(path, app.attachmentHandler).MatcherFunc(pageNameMatcher).Queries("action", "attachment").Methods("GET")
+		router.HandleFunc(path, app.attachmentsHandler).MatcherFunc(pageNameMatcher).Queries("action", "attachments").Methods("GET")
+		router.HandleFunc(path, app.uploadHandler).MatcherFunc(pageNameMatcher).Queries("action", "upload").Methods("POST")
+		router.HandleFunc(path, app.deleteAttachmentHandler).MatcherFunc(pageNameMatcher).Queries("action", "delete-attachment").Methods("POST")
+
+		// Rename
+		router.HandleFunc(path, app.renameHandler).MatcherFunc(pageNameMatcher).Queries("action", "rename").Methods("GET", "POST")
+
 		// History
+		router.HandleFunc(path, app.historyFeedHandler).MatcherFunc(pageNameMatcher).Queries("action", "history", "format", "{format}").Methods("GET")
 		router.HandleFunc(path, app.historyHandler).MatcherFunc(pageNameMatcher).Queries("action", "history").Methods("GET")
 
+		// Diff between revisions
+		router.HandleFunc(path, app.compareHandler).MatcherFunc(pageNameMatcher).Queries("action", "diff").Methods("GET")
+
 		// Diff
 		router.HandleFunc(path, app.diffHandler).
 			MatcherFunc(pageNameMatcher).
@@ -86,10 +141,132 @@ func main() {
 		router.HandleFunc(path, app.viewHandler).MatcherFunc(pageNameMatcher).Methods("GET")
 	}
 
+	api := router.PathPrefix(apiPrefix).Subrouter()
+	api.HandleFunc("/deleted", app.apiDeletedHandler).Methods("GET")
+	api.HandleFunc("/diff/{title:.+}", app.apiDiffHandler).Methods("GET")
+	api.HandleFunc("/history/{title:.+}", app.apiHistoryHandler).Methods("GET")
+	api.HandleFunc("/pages", app.apiPagesHandler).Methods("GET")
+	api.HandleFunc("/pages/{title:.+}", app.apiPageHandler).Methods("GET")
+	api.HandleFunc("/pages/{title:.+}", app.apiSavePageHandler).Methods("PUT")
+	api.HandleFunc("/pages/{title:.+}", app.apiDeletePageHandler).Methods("DELETE")
+	api.HandleFunc("/search", app.apiSearchHandler).Methods("GET")
+	api.NotFoundHandler = http.HandlerFunc(app.apiNotFoundHandler)
+
+	router.HandleFunc("/_/acl", app.aclHandler).Methods("GET", "POST")
+	router.HandleFunc("/_/backlinks", app.backlinksHandler).Methods("GET")
 	router.HandleFunc("/_/deleted", app.deletedHandler).Methods("GET")
+	router.HandleFunc("/_/login", app.loginHandler).Methods("GET", "POST")
+	router.HandleFunc("/_/logout", app.logoutHandler).Methods("POST")
 	router.HandleFunc("/_/pages", app.allPagesHandler).Methods("GET")
+	router.HandleFunc("/_/recent-changes", app.recentChangesFeedHandler).Queries("format", "{format}").Methods("GET")
+	router.HandleFunc("/_/recent-changes", app.recentChangesHandler).Methods("GET")
 	router.HandleFunc("/_/search", app.searchHandler).Methods("GET")
+	router.HandleFunc("/_/suggest", app.suggestHandler).Methods("GET")
+	router.HandleFunc("/_/webhooks", app.webhooksHandler).Methods("GET", "POST")
+	router.HandleFunc("/_/worktree", app.workTreeHandler).Methods("GET", "POST")
+
+	return app.Auth.Middleware(CSRFMiddleware(router))
+}
+
+func main() {
+	var addr string
+	var dataDir string
+	var storageType string
+	var rawHTML bool
+	var authMode string
+	var authHeader string
+	var authEmailHeader string
+	var commitExternalEdits bool
+	var admins string
+	flag.StringVar(&addr, "addr", "127.0.0.1:8000", "TCP address to listen on")
+	flag.StringVar(&dataDir, "data-dir", "data", "Data directory")
+	flag.StringVar(&storageType, "storage", "git", "Storage backend: git or memory")
+	flag.BoolVar(&rawHTML, "raw-html", false, "Render the HTML of pages unsanitized (trusted deployments only)")
+	flag.StringVar(&authMode, "auth", authNone, "Authentication: none, local (accounts in the data directory) or proxy (trust a header)")
+	flag.StringVar(&authHeader, "auth-header", "X-Forwarded-User", "Header holding the user name in proxy authentication")
+	flag.StringVar(&authEmailHeader, "auth-email-header", "X-Forwarded-Email", "Header holding the user email in proxy authentication")
+	flag.StringVar(&admins, "admins", "", "Users and groups (@group), separated by commas, with the admin right on every page")
+	flag.BoolVar(&commitExternalEdits, "commit-external-edits", false, "Commit the changes made to the data directory outside of the wiki instead of refusing to write")
+	flag.Parse()
+
+	users := NewUserStore(filepath.Join(dataDir, stateDir, "users.json"))
+	if flag.Arg(0) == "adduser" {
+		if err := addUser(users, flag.Args()[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	auth, err := NewAuthenticator(authMode, users)
+	if err != nil {
+		log.Fatal(err)
+	}
+	auth.Header = authHeader
+	auth.EmailHeader = authEmailHeader
+
+	var storage PageStore
+	var gitStorage *GitStorage
+	switch storageType {
+	case "git":
+		gitStorage = NewGitStorage(dataDir, pagesDir, pageExtension, attachmentsDir, stateDir)
+		gitStorage.CommitExternalEdits = commitExternalEdits
+		if err := gitStorage.Init(); err != nil {
+			log.Fatal(err)
+		}
+		storage = gitStorage
+	case "memory":
+		storage = NewMemoryStorage()
+	default:
+		log.Fatal("Unknown storage backend: " + storageType)
+	}
+
+	links, err := NewLinkIndex(storage)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	templates, err := NewTemplates()
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Without explicit rules, the wiki stays open to whoever can log in
+	fallback := principalUsers
+	if authMode == authNone {
+		fallback = principalEveryone
+	}
+
+	search, err := NewSearchIndex(links, filepath.Join(dataDir, stateDir, "search-index.json"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	webhooks := NewWebhooks(search, filepath.Join(dataDir, stateDir, "webhooks.json"))
+
+	app := AppContext{
+		Storage:   webhooks,
+		Links:     links,
+		Search:    search,
+		Auth:      auth,
+		ACL:       NewACL(filepath.Join(dataDir, stateDir, "acl.json"), fallback, splitList(admins)),
+		Webhooks:  webhooks,
+		WorkTree:  gitStorage,
+		templates: templates,
+	}
+	if !rawHTML {
+		app.sanitizer = NewSanitizer()
+	}
+
+	// Save the search index before exiting
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		search.Close()
+		os.Exit(0)
+	}()
 
 	log.Println("Listening on", addr)
-	log.Fatal(http.ListenAndServe(addr, router))
+	err = http.ListenAndServe(addr, app.handler())
+	search.Close()
+	log.Fatal(err)
 }